@@ -0,0 +1,497 @@
+/*
+Package child forwards the signals trapped by the goodbye package to the
+process's children, and waits for the children to exit before the
+remaining exit handlers are executed.
+
+	g := child.New(10 * time.Second)
+	cmd := exec.Command("worker")
+	if _, err := g.Start(cmd); err != nil {
+		log.Fatal(err)
+	}
+
+A Group created with WithKill kills the children that do not exit before
+the timeout expires, and its exit handler returns a *KillError that lists
+them. The Terminate function sends SIGTERM to the children and kills the
+ones that do not exit in the same way.
+
+On Linux, the children started by a Group created with WithDeathSignal
+are sent the signal by the kernel if the process dies without executing
+its exit handlers, ex. when it is killed with SIGKILL.
+
+On Windows, the children are assigned to job objects that kill them when
+the process dies, since Windows has neither process groups nor a
+parent-death signal.
+
+A Group waits for the children added to it, so the Wait function of a
+child's exec.Cmd must not be invoked. Use the Wait function of the Child
+that is returned instead.
+*/
+package child
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// ErrNotStarted is returned when a command that has not been started is
+// added to a Group.
+var ErrNotStarted = errors.New("child: command not started")
+
+// Option configures a Group.
+type Option func(c *config)
+
+type config struct {
+	m        *goodbye.Manager
+	name     string
+	priority int
+	pgroup   bool
+	kill     bool
+	exitSig  os.Signal
+	deathSig os.Signal
+	observed []os.Signal
+}
+
+// WithManager returns an Option that registers the exit handler with the
+// Manager instead of the default Manager.
+func WithManager(m *goodbye.Manager) Option {
+	return func(c *config) {
+		c.m = m
+	}
+}
+
+// WithName returns an Option that sets the name of the exit handler. The
+// default name is "children".
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithPriority returns an Option that sets the priority of the exit
+// handler. The default value is the priority of goodbye.PhaseDrain.
+func WithPriority(priority int) Option {
+	return func(c *config) {
+		c.priority = priority
+	}
+}
+
+// WithProcessGroups returns an Option that sets whether signals are sent
+// to the process group of each child instead of only to the child. The
+// child must be the leader of its process group, ex. by starting it with
+// the Setpgid field of its SysProcAttr set to true.
+//
+// On Windows, which does not have process groups, each child is assigned
+// to a job object instead, and killing the child terminates the processes
+// in its job. The processes that the child starts before it is added to
+// the Group are not in the job. The job is closed when the child exits,
+// which terminates the processes that remain in it.
+func WithProcessGroups(enabled bool) Option {
+	return func(c *config) {
+		c.pgroup = enabled
+	}
+}
+
+// WithExitSignal returns an Option that sets the signal sent to the
+// children when the process exits normally, i.e. when the goodbye.Exit
+// function is invoked. The default value is SIGTERM, or the interrupt note
+// on Plan 9. A nil signal means no signal is sent.
+func WithExitSignal(sig os.Signal) Option {
+	return func(c *config) {
+		c.exitSig = sig
+	}
+}
+
+// WithObservedSignals returns an Option that forwards the signals to the
+// children each time they are received without causing the process to
+// exit, ex. SIGHUP or SIGUSR1. The signals are observed with the Manager's
+// Observe function.
+func WithObservedSignals(sigs ...os.Signal) Option {
+	return func(c *config) {
+		c.observed = append(c.observed, sigs...)
+	}
+}
+
+// WithKill returns an Option that sets whether the children that do not
+// exit before the timeout expires are killed with SIGKILL, or their process
+// groups when used with WithProcessGroups. The exit handler then returns a
+// *KillError that lists the children that were killed.
+func WithKill(enabled bool) Option {
+	return func(c *config) {
+		c.kill = enabled
+	}
+}
+
+// WithDeathSignal returns an Option that sets the signal the kernel sends
+// to the children started with the Group's Start function if the process
+// dies without executing its exit handlers. Please see the SetDeathSignal
+// function. On Windows, each child is assigned to a job object that is
+// terminated when the process dies, as with WithProcessGroups.
+func WithDeathSignal(sig os.Signal) Option {
+	return func(c *config) {
+		c.deathSig = sig
+	}
+}
+
+// KillError is the error returned when children are killed because they
+// did not exit before the timeout expired.
+type KillError struct {
+	// Pids is the list of process IDs of the children that were killed.
+	Pids []int
+}
+
+func (e *KillError) Error() string {
+	return fmt.Sprintf("child: killed %d children that did not exit: %v",
+		len(e.Pids), e.Pids)
+}
+
+// Group is a set of child processes to which the trapped signals are
+// forwarded.
+type Group struct {
+	timeout    time.Duration
+	pgroup     bool
+	kill       bool
+	exitSig    os.Signal
+	deathSig   os.Signal
+	unregister []func()
+
+	children map[*Child]struct{}
+	idle     chan struct{}
+	closed   bool
+	sync.Mutex
+}
+
+// Child is a child process that was added to a Group.
+type Child struct {
+	g      *Group
+	p      *os.Process
+	done   chan struct{}
+	err    error
+	killed bool
+	sys    sysChild
+}
+
+// New returns a Group that forwards the Manager's trapped signals to its
+// children and registers an exit handler that waits for them to exit.
+//
+// A trapped signal is forwarded as soon as it is received with the
+// Manager's Relay function, which includes the signal that causes the
+// shutdown and the signals received while the exit handlers are running.
+// A shutdown that is vetoed by a preparer therefore does not prevent the
+// children from receiving the signal. When the process exits normally, the
+// exit signal is forwarded once the shutdown begins, before the critical
+// exit handlers are executed.
+//
+// If the children do not exit before the timeout expires then the handler
+// returns goodbye.ErrTimeout, or kills them if the Group was created with
+// WithKill. A timeout of zero means the wait is bounded only by the grace
+// period.
+func New(timeout time.Duration, opts ...Option) *Group {
+	c := config{
+		m:        goodbye.Default(),
+		name:     "children",
+		priority: goodbye.PhaseDrain.Priority,
+		exitSig:  terminate,
+	}
+	for _, o := range opts {
+		o(&c)
+	}
+
+	g := &Group{
+		timeout:  timeout,
+		pgroup:   c.pgroup,
+		kill:     c.kill,
+		deathSig: c.deathSig,
+		exitSig:  c.exitSig,
+		children: map[*Child]struct{}{},
+	}
+	g.forward(c.m, c.observed)
+	g.unregister = append(g.unregister, c.m.RegisterNamedFunc(c.name, func(ctx context.Context, s os.Signal) error {
+		err := g.Wait(ctx)
+		if err == goodbye.ErrTimeout && g.kill {
+			return g.killAll(ctx)
+		}
+		return err
+	}, c.priority))
+	return g
+}
+
+// forward starts forwarding the Manager's trapped signals, the observed
+// signals, and the exit signal to the children. The functions that stop
+// forwarding are added to the Group's unregister functions.
+func (g *Group) forward(m *goodbye.Manager, observed []os.Signal) {
+	// The Manager does not send to the channel once StopRelay returns, so
+	// it may then be closed.
+	relayc := make(chan os.Signal, 8)
+	m.Relay(relayc)
+	go func() {
+		for s := range relayc {
+			g.Signal(s)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		if s, _, err := m.Wait(ctx); err == nil && goodbye.IsNormalExit(s) && g.exitSig != nil {
+			g.Signal(g.exitSig)
+		}
+	}()
+
+	g.unregister = append(g.unregister, func() {
+		m.StopRelay(relayc)
+		close(relayc)
+		cancel()
+	})
+	for _, sig := range observed {
+		g.unregister = append(g.unregister, m.Observe(sig, func(ctx context.Context, s os.Signal) {
+			g.Signal(s)
+		}))
+	}
+}
+
+// Start starts the command and adds it to the Group. If the Group was
+// created with WithDeathSignal then the signal is set on the command before
+// it is started.
+func (g *Group) Start(cmd *exec.Cmd) (*Child, error) {
+	if g.deathSig != nil {
+		SetDeathSignal(cmd, g.deathSig)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return g.add(cmd.Process, cmd.Wait), nil
+}
+
+// Add adds a command that has been started to the Group. ErrNotStarted is
+// returned if the command has not been started.
+func (g *Group) Add(cmd *exec.Cmd) (*Child, error) {
+	if cmd.Process == nil {
+		return nil, ErrNotStarted
+	}
+	return g.add(cmd.Process, cmd.Wait), nil
+}
+
+// AddProcess adds a child process to the Group.
+func (g *Group) AddProcess(p *os.Process) *Child {
+	return g.add(p, func() error {
+		st, err := p.Wait()
+		if err != nil {
+			return err
+		}
+		if !st.Success() {
+			return &exec.ExitError{ProcessState: st}
+		}
+		return nil
+	})
+}
+
+// AddPID adds the child process with the specified process ID to the
+// Group.
+func (g *Group) AddPID(pid int) (*Child, error) {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+	return g.AddProcess(p), nil
+}
+
+func (g *Group) add(p *os.Process, wait func() error) *Child {
+	c := &Child{g: g, p: p, done: make(chan struct{})}
+	c.sys = attach(p, g.pgroup || g.deathSig != nil)
+
+	g.Lock()
+	g.children[c] = struct{}{}
+	g.Unlock()
+
+	go func() {
+		c.err = wait()
+		c.sys.release()
+		close(c.done)
+		g.remove(c)
+	}()
+	return c
+}
+
+// remove stops tracking a child that has exited.
+func (g *Group) remove(c *Child) {
+	g.Lock()
+	defer g.Unlock()
+	delete(g.children, c)
+	if len(g.children) == 0 && g.idle != nil {
+		close(g.idle)
+		g.idle = nil
+	}
+}
+
+// Len returns the number of children that have not exited.
+func (g *Group) Len() int {
+	g.Lock()
+	defer g.Unlock()
+	return len(g.children)
+}
+
+// list returns the children that have not exited.
+func (g *Group) list() []*Child {
+	g.Lock()
+	defer g.Unlock()
+	a := make([]*Child, 0, len(g.children))
+	for c := range g.children {
+		a = append(a, c)
+	}
+	return a
+}
+
+// Signal sends the signal to each child that has not exited, or to its
+// process group if the Group was created with WithProcessGroups. Signals
+// are not sent once the Group is closed.
+func (g *Group) Signal(sig os.Signal) error {
+	g.Lock()
+	closed := g.closed
+	g.Unlock()
+	if closed {
+		return nil
+	}
+	return g.signalAll(sig)
+}
+
+// signalAll sends the signal to each child that has not exited.
+func (g *Group) signalAll(sig os.Signal) error {
+	var errs goodbye.Errors
+	for _, c := range g.list() {
+		if err := signal(c, sig, g.pgroup); err != nil {
+			errs = append(errs, fmt.Errorf("child %d: %v", c.p.Pid, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Wait waits for the children to exit. If the timeout or the context
+// expires first then goodbye.ErrTimeout or the context's error is
+// returned.
+func (g *Group) Wait(ctx context.Context) error {
+	return g.wait(ctx, g.timeout)
+}
+
+// Terminate sends SIGTERM to the children and waits for them to exit. The
+// children that do not exit before the grace period expires are killed and
+// a *KillError that lists them is returned. A grace period of zero means
+// the children are killed if they have not exited when the context
+// expires.
+func (g *Group) Terminate(ctx context.Context, grace time.Duration) error {
+	g.signalAll(terminate)
+	err := g.wait(ctx, grace)
+	if err == goodbye.ErrTimeout || err == ctx.Err() {
+		return g.killAll(ctx)
+	}
+	return err
+}
+
+// killAll kills the children that have not exited and waits for them to be
+// reaped.
+func (g *Group) killAll(ctx context.Context) error {
+	a := g.list()
+	if len(a) == 0 {
+		return nil
+	}
+	e := &KillError{}
+	for _, c := range a {
+		if err := signal(c, os.Kill, g.pgroup); err != nil {
+			continue
+		}
+		g.Lock()
+		c.killed = true
+		g.Unlock()
+		e.Pids = append(e.Pids, c.p.Pid)
+	}
+	for _, c := range a {
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+		}
+	}
+	if len(e.Pids) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (g *Group) wait(ctx context.Context, timeout time.Duration) error {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+
+	g.Lock()
+	if len(g.children) == 0 {
+		g.Unlock()
+		return nil
+	}
+	if g.idle == nil {
+		g.idle = make(chan struct{})
+	}
+	idle := g.idle
+	g.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-expired:
+		return goodbye.ErrTimeout
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close removes the exit handler and stops forwarding signals to the
+// children. The children are not signaled or waited for.
+func (g *Group) Close() error {
+	g.Lock()
+	unregister := g.unregister
+	g.unregister, g.closed = nil, true
+	g.Unlock()
+	for _, f := range unregister {
+		f()
+	}
+	return nil
+}
+
+// Pid returns the child's process ID.
+func (c *Child) Pid() int {
+	return c.p.Pid
+}
+
+// Process returns the child's process.
+func (c *Child) Process() *os.Process {
+	return c.p
+}
+
+// Done returns a channel that is closed when the child exits.
+func (c *Child) Done() <-chan struct{} {
+	return c.done
+}
+
+// Killed returns true if the child was killed because it did not exit
+// before the timeout expired.
+func (c *Child) Killed() bool {
+	c.g.Lock()
+	defer c.g.Unlock()
+	return c.killed
+}
+
+// Wait waits for the child to exit and returns the error returned by the
+// Wait function of its exec.Cmd or os.Process.
+func (c *Child) Wait() error {
+	<-c.done
+	return c.err
+}
@@ -0,0 +1,26 @@
+package child
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// SetDeathSignal configures the command so that the kernel sends the
+// signal to the child if the parent dies, even if the parent is killed
+// with SIGKILL. It must be invoked before the command is started. The
+// signal is sent when the thread that started the child exits, which the
+// Go runtime does only if a goroutine that locked its thread exits without
+// unlocking it. The returned boolean is false on platforms that do not
+// support a parent-death signal, or if the signal is not a syscall.Signal.
+func SetDeathSignal(cmd *exec.Cmd, sig os.Signal) bool {
+	n, ok := sig.(syscall.Signal)
+	if !ok {
+		return false
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Pdeathsig = n
+	return true
+}
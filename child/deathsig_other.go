@@ -0,0 +1,16 @@
+// +build !linux
+
+package child
+
+import (
+	"os"
+	"os/exec"
+)
+
+// SetDeathSignal configures the command so that the kernel sends the
+// signal to the child if the parent dies. It does nothing on this
+// platform, which does not support a parent-death signal, and returns
+// false.
+func SetDeathSignal(cmd *exec.Cmd, sig os.Signal) bool {
+	return false
+}
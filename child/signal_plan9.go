@@ -0,0 +1,24 @@
+// +build plan9
+
+package child
+
+import "os"
+
+// terminate is the note posted to ask a child to exit.
+var terminate = os.Interrupt
+
+// sysChild is the platform-specific state of a child.
+type sysChild struct{}
+
+// attach does nothing since Plan 9 does not have process groups.
+func attach(p *os.Process, enabled bool) sysChild {
+	return sysChild{}
+}
+
+func (sysChild) release() {}
+
+// signal posts the note to the child. Plan 9 does not have process groups,
+// so pgroup is ignored.
+func signal(c *Child, sig os.Signal, pgroup bool) error {
+	return c.p.Signal(sig)
+}
@@ -0,0 +1,31 @@
+// +build !windows,!plan9
+
+package child
+
+import (
+	"os"
+	"syscall"
+)
+
+// terminate is the signal sent to ask a child to exit.
+var terminate os.Signal = syscall.SIGTERM
+
+// sysChild is the platform-specific state of a child.
+type sysChild struct{}
+
+// attach does nothing since process groups are created by the child.
+func attach(p *os.Process, enabled bool) sysChild {
+	return sysChild{}
+}
+
+func (sysChild) release() {}
+
+// signal sends the signal to the child, or to its process group if pgroup
+// is true.
+func signal(c *Child, sig os.Signal, pgroup bool) error {
+	n, ok := sig.(syscall.Signal)
+	if !pgroup || !ok {
+		return c.p.Signal(sig)
+	}
+	return syscall.Kill(-c.p.Pid, n)
+}
@@ -0,0 +1,158 @@
+// +build windows
+
+package child
+
+import (
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modkernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	procCreateJobObjectW         = modkernel32.NewProc("CreateJobObjectW")
+	procSetInformationJobObject  = modkernel32.NewProc("SetInformationJobObject")
+	procAssignProcessToJobObject = modkernel32.NewProc("AssignProcessToJobObject")
+	procTerminateJobObject       = modkernel32.NewProc("TerminateJobObject")
+)
+
+const (
+	jobObjectInfoExtendedLimit   = 9
+	jobObjectLimitKillOnJobClose = 0x2000
+
+	processSetQuota  = 0x0100
+	processTerminate = 0x0001
+)
+
+type jobObjectBasicLimitInformation struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+}
+
+type ioCounters struct {
+	ReadOperationCount  uint64
+	WriteOperationCount uint64
+	OtherOperationCount uint64
+	ReadTransferCount   uint64
+	WriteTransferCount  uint64
+	OtherTransferCount  uint64
+}
+
+type jobObjectExtendedLimitInformation struct {
+	BasicLimitInformation jobObjectBasicLimitInformation
+	IoInfo                ioCounters
+	ProcessMemoryLimit    uintptr
+	JobMemoryLimit        uintptr
+	PeakProcessMemoryUsed uintptr
+	PeakJobMemoryUsed     uintptr
+}
+
+// terminate is the signal sent to ask a child to exit. Windows does not
+// support sending it, so it has no effect.
+var terminate os.Signal = syscall.SIGTERM
+
+// sysChild is the platform-specific state of a child. It is the job
+// object to which the child is assigned, or nil.
+type sysChild struct {
+	*job
+}
+
+// job is a job object that is closed once.
+type job struct {
+	h      syscall.Handle
+	closed bool
+	sync.Mutex
+}
+
+// attach assigns the process to a new job object that terminates its
+// processes when it is closed. The zero value is returned if enabled is
+// false or the job object cannot be created, in which case only the
+// process itself is killed.
+func attach(p *os.Process, enabled bool) sysChild {
+	if !enabled {
+		return sysChild{}
+	}
+	j, err := createJob()
+	if err != nil {
+		return sysChild{}
+	}
+	h, err := syscall.OpenProcess(
+		processSetQuota|processTerminate, false, uint32(p.Pid))
+	if err != nil {
+		syscall.CloseHandle(j)
+		return sysChild{}
+	}
+	defer syscall.CloseHandle(h)
+	if r, _, _ := procAssignProcessToJobObject.Call(
+		uintptr(j), uintptr(h)); r == 0 {
+		syscall.CloseHandle(j)
+		return sysChild{}
+	}
+	return sysChild{&job{h: j}}
+}
+
+// createJob creates a job object that terminates its processes when it is
+// closed.
+func createJob() (syscall.Handle, error) {
+	r, _, err := procCreateJobObjectW.Call(0, 0)
+	if r == 0 {
+		return 0, err
+	}
+	h := syscall.Handle(r)
+
+	var info jobObjectExtendedLimitInformation
+	info.BasicLimitInformation.LimitFlags = jobObjectLimitKillOnJobClose
+	if r, _, err := procSetInformationJobObject.Call(
+		uintptr(h), jobObjectInfoExtendedLimit,
+		uintptr(unsafe.Pointer(&info)), unsafe.Sizeof(info)); r == 0 {
+		syscall.CloseHandle(h)
+		return 0, err
+	}
+	return h, nil
+}
+
+// release closes the job object, which terminates the processes that remain
+// in it.
+func (c sysChild) release() {
+	if c.job == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	if !c.closed {
+		c.closed = true
+		syscall.CloseHandle(c.h)
+	}
+}
+
+// terminate terminates the processes in the job object.
+func (j *job) terminate() error {
+	j.Lock()
+	defer j.Unlock()
+	if j.closed {
+		return nil
+	}
+	if r, _, err := procTerminateJobObject.Call(uintptr(j.h), 1); r == 0 {
+		return err
+	}
+	return nil
+}
+
+// signal sends the signal to the child. The only signal that may be sent
+// to a process on Windows is os.Kill, which terminates the processes in
+// the child's job object if pgroup is true.
+func signal(c *Child, sig os.Signal, pgroup bool) error {
+	if !pgroup || sig != os.Kill || c.sys.job == nil {
+		return c.p.Signal(sig)
+	}
+	return c.sys.terminate()
+}
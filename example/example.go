@@ -17,12 +17,12 @@ func main() {
 	// safe to execute no matter what.
 	defer goodbye.Exit(ctx, -1)
 
-	// Invoke `goodbye.Notify` to begin trapping signals this process
-	// might receive. The Notify function can specify which signals are
-	// trapped, but if none are specified then a default list is used.
-	// The default set is platform dependent. See the files
+	// Invoke `goodbye.NotifySignals` to begin trapping signals this
+	// process might receive. The NotifySignals function can specify which
+	// signals are trapped, but if none are specified then a default list
+	// is used. The default set is platform dependent. See the files
 	// "goodbye_GOOS.go" for more information.
-	goodbye.Notify(ctx)
+	goodbye.NotifySignals(ctx)
 
 	// Register two functions that will be executed when this process
 	// exits.
@@ -64,7 +64,6 @@ func main() {
 	// program is killed with a signal -- either from the "kill" command
 	// or a CTRL-C.
 	if strings.EqualFold("wait", os.Args[1]) {
-		c := make(chan int)
-		<-c
+		goodbye.Wait(ctx)
 	}
 }
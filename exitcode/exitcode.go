@@ -0,0 +1,123 @@
+/*
+Package exitcode provides the exit codes defined by the BSD sysexits.h
+header and an ExitCodePolicy for the goodbye package based on them.
+
+	goodbye.SetExitCodePolicy(exitcode.Sysexits)
+*/
+package exitcode
+
+import (
+	"os"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// The exit codes defined by sysexits.h.
+const (
+	// OK indicates successful termination.
+	OK = 0
+
+	// Usage indicates the command was used incorrectly.
+	Usage = 64
+
+	// DataErr indicates the input data was incorrect.
+	DataErr = 65
+
+	// NoInput indicates an input file did not exist or was not readable.
+	NoInput = 66
+
+	// NoUser indicates the specified user did not exist.
+	NoUser = 67
+
+	// NoHost indicates the specified host did not exist.
+	NoHost = 68
+
+	// Unavailable indicates a service is unavailable.
+	Unavailable = 69
+
+	// Software indicates an internal software error was detected.
+	Software = 70
+
+	// OSErr indicates an operating system error was detected.
+	OSErr = 71
+
+	// OSFile indicates a system file did not exist or had an error.
+	OSFile = 72
+
+	// CantCreat indicates an output file could not be created.
+	CantCreat = 73
+
+	// IOErr indicates an error occurred while doing I/O.
+	IOErr = 74
+
+	// TempFail indicates a temporary failure. The user is invited to
+	// retry.
+	TempFail = 75
+
+	// Protocol indicates the remote system returned something invalid
+	// during a protocol exchange.
+	Protocol = 76
+
+	// NoPerm indicates insufficient permission to perform an operation.
+	NoPerm = 77
+
+	// Config indicates something was found in an unconfigured or
+	// misconfigured state.
+	Config = 78
+)
+
+// Sysexits is an ExitCodePolicy that computes the exit code as follows:
+//
+//   - A non-zero exit code provided to Exit or bound to the signal is
+//     used as is.
+//   - If an exit handler returned an error that is or wraps a
+//     goodbye.ExitCoder then the highest such code is used.
+//   - If an exit handler timed out then TempFail is used.
+//   - If an exit handler returned any other error then Software is used.
+//   - Otherwise OK is used.
+var Sysexits goodbye.ExitCodePolicy = goodbye.ExitCodePolicyFunc(sysexits)
+
+func sysexits(s os.Signal, err error, code int) int {
+	if code != 0 {
+		return code
+	}
+	errs, _ := err.(goodbye.Errors)
+	if len(errs) == 0 && err != nil {
+		errs = goodbye.Errors{err}
+	}
+
+	var (
+		max      int
+		found    bool
+		timedOut bool
+	)
+	for _, err := range errs {
+		for err != nil {
+			if ec, ok := err.(goodbye.ExitCoder); ok {
+				if c := ec.ExitCode(); !found || c > max {
+					max, found = c, true
+				}
+				break
+			}
+			if err == goodbye.ErrTimeout {
+				timedOut = true
+				break
+			}
+			u, ok := err.(interface{ Unwrap() error })
+			if !ok {
+				break
+			}
+			err = u.Unwrap()
+		}
+	}
+
+	switch {
+	case found:
+		return max
+	case timedOut:
+		return TempFail
+	case len(errs) > 0:
+		return Software
+	}
+	return OK
+}
@@ -6,16 +6,54 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/signal"
-	"sort"
-	"sync"
+	"time"
 )
 
 // ExitHandler is a function that is registerd with the "Register" function
 // and is invoked when this process exits, either normally or due to a process
 // signal.
+//
+// The context provided to an exit handler has a deadline if the Manager has
+// a grace period or the handler's priority level has a timeout, whichever
+// expires first.
 type ExitHandler func(ctx context.Context, s os.Signal)
 
+// ExitFunc is an exit handler that returns an error. The errors returned
+// by exit handlers are collected and may be observed with the OnError
+// function. Please see the SetErrorExitCode function for how errors affect
+// the process's exit code.
+type ExitFunc func(ctx context.Context, s os.Signal) error
+
+// SignalSpec specifies a signal to trap and the exit code with which the
+// process exits when the signal is received.
+type SignalSpec struct {
+	// Signal is the signal to trap.
+	Signal os.Signal
+
+	// ExitCode is the process's exit code when the signal is received.
+	ExitCode int
+}
+
+// Registrar is the interface implemented by types with which exit handlers
+// may be registered. Libraries that register exit handlers may accept a
+// Registrar so that tests can provide a fake implementation, such as the
+// one in the goodbyetest package.
+type Registrar interface {
+	Register(f ExitHandler) func()
+	RegisterWithPriority(f ExitHandler, priority int) func()
+	RegisterFunc(f ExitFunc) func()
+	RegisterFuncWithPriority(f ExitFunc, priority int) func()
+	RegisterNamed(name string, f ExitHandler, priority int) func()
+	RegisterNamedFunc(name string, f ExitFunc, priority int) func()
+}
+
+var _ Registrar = (*Manager)(nil)
+
+// DefaultForcedExitCode is the default exit code used when the process is
+// forcibly exited because the grace period expired. It matches the exit
+// code used by the timeout(1) command.
+const DefaultForcedExitCode = 124
+
 type noSig struct {
 }
 
@@ -37,11 +75,6 @@ var (
 	// with an exit code value of -1.
 	ExitCode int
 
-	// handlers is a list of exit handlers to invoke when the process exits
-	// or receives a signal that causes an exit behavior
-	handlers    = map[int][]ExitHandler{}
-	handlersRWL sync.RWMutex
-
 	// noSigVal is provided to the handleOnce function when Exit is invoked
 	// so that exit handlers can use the IsNormalExit function to determine
 	// if the process is exiting normally or due to a process signal.
@@ -51,27 +84,26 @@ var (
 	// if the Notify function is invoked with an empty signals argument value
 	defaultSignals map[os.Signal]int
 
-	// notified is the list of signals that are trapped as a result of the
-	// Notify function. This list is what the Reset function uses when undoing
-	// the effects of the Notify function.
-	notified []os.Signal
-
-	// once is used by the handleOnce function to execute the exit handlers
-	// and os.Exit exactly once, regardless of how many times Exit is invoked
-	// or if a signal is received at the same time that Exit is invoked
-	once sync.Once
+	// untrappableSignals is the list of signals that cannot be trapped.
+	untrappableSignals map[os.Signal]bool
 
-	// lock is used to prevent the Exit, Notify, and Reset functions
-	// from being called concurrently.
-	lock sync.Mutex
+	// defaultManager is the Manager used by the package-level functions.
+	defaultManager = New()
 )
 
+// Default returns the Manager used by the package-level functions.
+func Default() *Manager {
+	return defaultManager
+}
+
 // Register registers a function to be invoked when this process exits
 // normally or due to a process signal.
 //
 // Handlers registered with this function are given a priority of 0.
-func Register(f ExitHandler) {
-	RegisterWithPriority(f, 0)
+//
+// The returned function removes the handler when invoked.
+func Register(f ExitHandler) func() {
+	return defaultManager.Register(f)
 }
 
 // RegisterWithPriority registers a function to be invoked when
@@ -82,14 +114,112 @@ func Register(f ExitHandler) {
 // execute later. If multiple handlers share the same priority level
 // then the handlers are invoked in the order in which they were
 // registered.
-func RegisterWithPriority(f ExitHandler, priority int) {
-	handlersRWL.Lock()
-	defer handlersRWL.Unlock()
-	if a, ok := handlers[priority]; !ok {
-		handlers[priority] = []ExitHandler{f}
-	} else {
-		handlers[priority] = append(a, f)
-	}
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func RegisterWithPriority(f ExitHandler, priority int) func() {
+	return defaultManager.RegisterWithPriority(f, priority)
+}
+
+// RegisterFunc registers a function that returns an error to be invoked
+// when this process exits normally or due to a process signal.
+//
+// Handlers registered with this function are given a priority of 0.
+//
+// The returned function removes the handler when invoked.
+func RegisterFunc(f ExitFunc) func() {
+	return defaultManager.RegisterFunc(f)
+}
+
+// RegisterFuncWithPriority registers a function that returns an error to
+// be invoked when this process exits normally or due to a process signal.
+// Please see RegisterWithPriority for a description of the priority.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func RegisterFuncWithPriority(f ExitFunc, priority int) func() {
+	return defaultManager.RegisterFuncWithPriority(f, priority)
+}
+
+// RegisterNamed registers a named function to be invoked when this process
+// exits normally or due to a process signal. Please see RegisterWithPriority
+// for a description of the priority.
+//
+// The name is used to identify the handler in errors and diagnostic output.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func RegisterNamed(name string, f ExitHandler, priority int) func() {
+	return defaultManager.RegisterNamed(name, f, priority)
+}
+
+// RegisterNamedFunc registers a named function that returns an error to be
+// invoked when this process exits normally or due to a process signal.
+// Please see RegisterWithPriority for a description of the priority.
+//
+// The name is used to identify the handler in errors and diagnostic output.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func RegisterNamedFunc(name string, f ExitFunc, priority int) func() {
+	return defaultManager.RegisterNamedFunc(name, f, priority)
+}
+
+// SetErrorExitCode sets the exit code used when one or more exit handlers
+// return an error and the process would otherwise exit with an exit code
+// of zero. The default value is 1. A value of 0 means handler errors do
+// not affect the exit code.
+func SetErrorExitCode(exitCode int) {
+	defaultManager.SetErrorExitCode(exitCode)
+}
+
+// OnError sets a function that is invoked after all of the exit handlers
+// have been executed if one or more of them returned an error. The error
+// provided to the function is of type Errors.
+func OnError(f func(ctx context.Context, err error)) {
+	defaultManager.OnError(f)
+}
+
+// SetGracePeriod sets the amount of time the exit handlers are given to
+// complete. If the handlers are still running when the grace period
+// expires then the process is forcibly exited with the exit code set with
+// SetForcedExitCode. A value of zero, the default, means there is no
+// grace period and the handlers may run indefinitely.
+func SetGracePeriod(d time.Duration) {
+	defaultManager.SetGracePeriod(d)
+}
+
+// SetSlowHandlerThreshold sets the amount of time after which an exit
+// handler that is still running is logged as slow. Please see the
+// Manager's SetSlowHandlerThreshold function.
+func SetSlowHandlerThreshold(d time.Duration) {
+	defaultManager.SetSlowHandlerThreshold(d)
+}
+
+// SetForcedExitCode sets the exit code used when the process is forcibly
+// exited because the grace period expired. The default value is
+// DefaultForcedExitCode.
+func SetForcedExitCode(exitCode int) {
+	defaultManager.SetForcedExitCode(exitCode)
+}
+
+// SetConcurrency sets the maximum number of exit handlers that share the
+// same priority level that may be executed concurrently. Priority levels
+// are always executed sequentially.
+//
+// A value of 0 or 1, the default, executes handlers one at a time in the
+// order in which they were registered. A negative value means there is no
+// limit.
+func SetConcurrency(n int) {
+	defaultManager.SetConcurrency(n)
+}
+
+// SetExiter sets the function used to exit the process after the exit
+// handlers have completed or the grace period has expired. The default
+// value is os.Exit. Tests may use this function to verify the exit code
+// without terminating the test binary. A nil value restores the default.
+func SetExiter(f func(code int)) {
+	defaultManager.SetExiter(f)
 }
 
 // IsNormalExit returns true if the program is exiting as a result of
@@ -98,120 +228,121 @@ func IsNormalExit(sig os.Signal) bool {
 	return sig == noSigVal
 }
 
+// NormalExitSignal returns the signal provided to exit handlers when the
+// program is exiting as a result of the Exit function being invoked. It
+// is the signal for which IsNormalExit returns true.
+func NormalExitSignal() os.Signal {
+	return noSigVal
+}
+
 // Exit executes all of the registered exit handlers.
 //
 // The handlers may use the IsNormalExit function and the signal provided
 // to the handler to check if the program is exiting normally or due to
 // a process signal.
-func Exit(ctx context.Context, exitCode int) {
-	lock.Lock()
-	defer lock.Unlock()
-	if exitCode < 0 {
+//
+// The options are applied before the handlers are executed, ex. to
+// shorten the grace period of a normal exit with WithGracePeriod.
+func Exit(ctx context.Context, exitCode int, opts ...Option) {
+	if exitCode < 0 && ExitCode != 0 {
 		exitCode = ExitCode
 	}
-	handleOnce(ctx, noSigVal, exitCode)
+	defaultManager.Exit(ctx, exitCode, opts...)
 }
 
 // Notify begins trapping the specified signals. This function should be
 // invoked as early as possible by the executing program.
 //
-// The signals argument accepts a series of os.Signal values. Any os.Signal
-// value in the list may be succeeded with an integer to be used as the
-// process's exit code when the associated signal is received. By default
-// the process will exit with an exit code of zero, indicating a graceful
-// shutdown.
+// Each invocation of Notify adds the signals to the signals trapped by
+// previous invocations. The exit code of a signal that is already trapped
+// is replaced. Please see the UnNotify function to stop trapping signals.
+//
+// The signals argument accepts a series of Option values, such as
+// WithSignals and WithGracePeriod, which configure the shutdown behavior:
+//
+//	goodbye.Notify(ctx,
+//		goodbye.WithSignals(goodbye.SignalSpec{Signal: syscall.SIGTERM}),
+//		goodbye.WithGracePeriod(30*time.Second))
+//
+// For compatibility the signals argument also accepts a series of os.Signal
+// values. Any os.Signal value in the list may be succeeded with an integer
+// to be used as the process's exit code when the associated signal is
+// received. By default the process will exit with an exit code of zero,
+// indicating a graceful shutdown. A signal may also be specified by its
+// name, ex. "SIGTERM". Please see the ParseSignal function for the
+// supported names. This form is deprecated in favor of the WithSignals
+// option, which does not rely on the position of an integer in the list to
+// determine its meaning.
 //
 // The default value for the signals variadic depends on the operating
 // system (OS):
 //
-//   UNIX
-//     SIGKILL, 1, SIGHUP, 0, SIGINT, 0, SIGQUIT, 0, SIGTERM, 0
-//
-//   Windows
-//     SIGKILL, 1, SIGHUP, 0, os.Interrupt, 0, SIGQUIT, 0, SIGTERM, 0
-func Notify(ctx context.Context, signals ...interface{}) {
-	lock.Lock()
-	defer lock.Unlock()
-
-	var sigs map[os.Signal]int
-	if len(signals) == 0 {
-		sigs = defaultSignals
-	} else {
-		sigs = map[os.Signal]int{}
-		var s os.Signal
-		for _, v := range signals {
-			switch tv := v.(type) {
-			case os.Signal:
-				s = tv
-				sigs[s] = 0
-			case int:
-				sigs[s] = tv
-			}
-		}
-	}
-
-	var (
-		i        = 0
-		sigc     = make(chan os.Signal, 1)
-		notified = make([]os.Signal, len(sigs))
-	)
-	for s := range sigs {
-		notified[i] = s
-		i++
-	}
-
-	signal.Notify(sigc, notified...)
-
-	go func() {
-		for s := range sigc {
-
-			// Get the exit code associated with the signal. If no
-			// exit code exists then the signal was not trapped and
-			// should not be handled.
-			x, ok := sigs[s]
-			if !ok {
-				continue
-			}
-
-			// Execute the signal handlers and exit the program.
-			handleOnce(ctx, s, x)
-		}
-	}()
+//	UNIX
+//	  SIGHUP, 0, SIGINT, 0, SIGQUIT, 0, SIGTERM, 0
+//
+//	Windows
+//	  SIGHUP, 0, os.Interrupt, 0, SIGQUIT, 0, SIGTERM, 0,
+//	  CtrlCloseEvent, 0, CtrlLogoffEvent, 0, CtrlShutdownEvent, 0
+//
+//	Plan 9
+//	  os.Interrupt, 0, "hangup" note, 0
+//
+//	js/wasm
+//	  PageBeforeUnload, 0, PageHide, 0
+//
+// The defaults never include signals that cannot be trapped, such as
+// SIGKILL.
+//
+// An error of type SignalError is returned and the trapped signals are not
+// changed if a signal cannot be trapped, such as SIGKILL or SIGSTOP, if a
+// signal is specified more than once, if a signal name is unknown, if an
+// integer is not preceded by a signal, or if a value is not one of the
+// types described above. Please see the SetStrict function for stricter
+// validation.
+func Notify(ctx context.Context, signals ...interface{}) error {
+	return defaultManager.Notify(ctx, signals...)
 }
 
-// Reset clears the list of registered exit handlers and stops trapping
-// the signals that were trapped as a result of the Notify function.
-func Reset() {
-	lock.Lock()
-	defer lock.Unlock()
-	signal.Reset(notified...)
-
-	handlersRWL.Lock()
-	defer handlersRWL.Unlock()
-	handlers = nil
+// NotifySignals begins trapping the specified signals. This function
+// should be invoked as early as possible by the executing program.
+//
+// Each SignalSpec specifies a signal to trap and the process's exit code
+// when the signal is received. If no specs are provided then the signals
+// from the Config applied with the ApplyConfig function are used, or, if
+// there are none, a default list that depends on the operating system.
+// Please see the Notify function for the default list.
+//
+// As with Notify, the signals are added to the signals that are already
+// trapped.
+//
+// An error of type SignalError is returned and the trapped signals are not
+// changed if a signal cannot be trapped, such as SIGKILL or SIGSTOP, or if
+// a signal is specified more than once.
+func NotifySignals(ctx context.Context, specs ...SignalSpec) error {
+	return defaultManager.NotifySignals(ctx, specs...)
 }
 
-func handleOnce(ctx context.Context, s os.Signal, x int) {
-	once.Do(func() {
-		handle(ctx, s)
-		os.Exit(x)
-	})
+// UnNotify stops trapping the specified signals. The other trapped signals
+// remain trapped. If no signals are specified then all of the signals
+// trapped as a result of the Notify functions are no longer trapped.
+// Signals that are not trapped are ignored.
+//
+// A signal that is no longer trapped or observed regains its default
+// behavior, ex. SIGTERM terminates the process without executing the exit
+// handlers.
+func UnNotify(signals ...os.Signal) {
+	defaultManager.UnNotify(signals...)
 }
 
-func handle(ctx context.Context, s os.Signal) {
-	handlersRWL.RLock()
-	defer handlersRWL.RUnlock()
-
-	keys := []int{}
-	for k := range handlers {
-		keys = append(keys, k)
-	}
-
-	sort.Ints(keys)
-
-	for _, k := range keys {
-		for _, h := range handlers[k] {
-			h(ctx, s)
-		}
-	}
+// Reset clears the list of registered exit handlers and observers and
+// stops trapping the signals that were trapped as a result of the Notify
+// and Observe functions.
+//
+// Reset also re-arms the library so that a subsequent trapped signal or
+// invocation of the Exit function executes the exit handlers registered
+// after Reset. The configuration set with the Set* and On* functions is
+// retained. Reset should not be invoked while the exit handlers are being
+// executed.
+func Reset() {
+	defaultManager.Reset()
 }
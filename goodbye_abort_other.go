@@ -0,0 +1,8 @@
+// +build windows js plan9 wasip1
+
+package goodbye
+
+// abort panics since the operating system cannot raise SIGABRT.
+func abort() {
+	panicAbort()
+}
@@ -0,0 +1,21 @@
+// +build !windows,!js,!plan9,!wasip1
+
+package goodbye
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// abort raises SIGABRT so that the Go runtime writes a traceback of all
+// goroutines and exits. SIGABRT is first restored to its default behavior
+// in case it is trapped. If the signal is not delivered then abort panics.
+func abort() {
+	signal.Reset(syscall.SIGABRT)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGABRT); err == nil {
+		time.Sleep(time.Second)
+	}
+	panicAbort()
+}
@@ -0,0 +1,57 @@
+// +build go1.8
+
+package goodbye
+
+import "os"
+
+// RegisterAction registers a named action to be performed each time this
+// process receives the specified signal. Actions are observers, so they do
+// not cause the process to exit. This is useful for mapping non-fatal
+// signals such as SIGUSR1 and SIGUSR2 to actions such as "dump stats" or
+// "toggle debug".
+//
+// Multiple actions may be registered for the same signal. They are
+// performed in the order in which they were registered, along with any
+// observers registered with the Observe function.
+//
+// The returned function removes the action when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterAction(sig os.Signal, name string, f ObserverFunc) func() {
+	return m.addObserver(&observer{f: f, sig: sig, name: name})
+}
+
+// Actions returns the names of the actions registered for the specified
+// signal in the order in which they are performed.
+func (m *Manager) Actions(sig os.Signal) []string {
+	m.observers.RLock()
+	defer m.observers.RUnlock()
+	var names []string
+	for _, o := range m.observers.byS[sig] {
+		if o.name != "" {
+			names = append(names, o.name)
+		}
+	}
+	return names
+}
+
+// RegisterAction registers a named action to be performed each time this
+// process receives the specified signal. Actions are observers, so they do
+// not cause the process to exit. This is useful for mapping non-fatal
+// signals such as SIGUSR1 and SIGUSR2 to actions such as "dump stats" or
+// "toggle debug".
+//
+// Multiple actions may be registered for the same signal. They are
+// performed in the order in which they were registered, along with any
+// observers registered with the Observe function.
+//
+// The returned function removes the action when invoked. It is safe to
+// invoke the returned function more than once.
+func RegisterAction(sig os.Signal, name string, f ObserverFunc) func() {
+	return defaultManager.RegisterAction(sig, name, f)
+}
+
+// Actions returns the names of the actions registered for the specified
+// signal in the order in which they are performed.
+func Actions(sig os.Signal) []string {
+	return defaultManager.Actions(sig)
+}
@@ -0,0 +1,27 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"os"
+)
+
+// RegisterCancel registers an exit handler that cancels a context, ex. the
+// context of a worker, as part of the ordered shutdown. Please see
+// RegisterWithPriority for a description of the priority.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterCancel(cancel context.CancelFunc, priority int) func() {
+	return m.RegisterFuncWithPriority(func(ctx context.Context, s os.Signal) error {
+		cancel()
+		return nil
+	}, priority)
+}
+
+// RegisterCancel registers an exit handler that cancels a context. Please
+// see the Manager's RegisterCancel function.
+func RegisterCancel(cancel context.CancelFunc, priority int) func() {
+	return defaultManager.RegisterCancel(cancel, priority)
+}
@@ -0,0 +1,50 @@
+//go:build go1.20
+// +build go1.20
+
+package goodbye
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// ShutdownError is the cause with which RegisterCancelCause cancels a
+// context when the process exits.
+type ShutdownError struct {
+	// Signal is the signal that caused the shutdown. It is the value
+	// returned by NormalExitSignal if the shutdown was caused by Exit.
+	Signal os.Signal
+}
+
+func (e *ShutdownError) Error() string {
+	if IsNormalExit(e.Signal) {
+		return "goodbye: process exiting"
+	}
+	return fmt.Sprintf("goodbye: process exiting due to signal %s", e.Signal)
+}
+
+// RegisterCancelCause registers an exit handler that cancels a context with
+// a cause as part of the ordered shutdown. The cause is the error provided
+// to ExitErr if the shutdown was caused by ExitErr, and otherwise is a
+// *ShutdownError. Please see RegisterWithPriority for a description of the
+// priority.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterCancelCause(cancel context.CancelCauseFunc, priority int) func() {
+	return m.RegisterFuncWithPriority(func(ctx context.Context, s os.Signal) error {
+		cause := ExitError(ctx)
+		if cause == nil {
+			cause = &ShutdownError{Signal: s}
+		}
+		cancel(cause)
+		return nil
+	}, priority)
+}
+
+// RegisterCancelCause registers an exit handler that cancels a context with
+// a cause. Please see the Manager's RegisterCancelCause function.
+func RegisterCancelCause(cancel context.CancelCauseFunc, priority int) func() {
+	return defaultManager.RegisterCancelCause(cancel, priority)
+}
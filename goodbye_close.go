@@ -0,0 +1,100 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// CloseOption configures the exit handlers registered by the functions
+// that close resources, such as RegisterDB.
+type CloseOption func(c *closeConfig)
+
+type closeConfig struct {
+	timeout     time.Duration
+	beforeClose []func(ctx context.Context) error
+}
+
+// CloseTimeout returns a CloseOption that limits the amount of time the
+// resource is given to close. If the timeout expires then the exit handler
+// returns an error that wraps ErrTimeout without waiting for the resource
+// to close. A timeout of zero, the default, means closing the resource is
+// bounded only by the grace period.
+func CloseTimeout(d time.Duration) CloseOption {
+	return func(c *closeConfig) {
+		c.timeout = d
+	}
+}
+
+// BeforeClose returns a CloseOption that adds a function that is invoked
+// before the resource is closed, ex. to stop issuing new queries to a
+// database. If the function returns an error then the error is returned
+// by the exit handler, but the resource is still closed.
+func BeforeClose(f func(ctx context.Context) error) CloseOption {
+	return func(c *closeConfig) {
+		c.beforeClose = append(c.beforeClose, f)
+	}
+}
+
+// closeFunc returns an ExitFunc that invokes the BeforeClose functions and
+// then closes the resource with f.
+func closeFunc(f func() error, opts []CloseOption) ExitFunc {
+	var c closeConfig
+	for _, o := range opts {
+		if o != nil {
+			o(&c)
+		}
+	}
+	return func(ctx context.Context, s os.Signal) error {
+		var errs Errors
+		for _, bf := range c.beforeClose {
+			if err := bf(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := closeWithTimeout(f, c.timeout); err != nil {
+			errs = append(errs, err)
+		}
+		if len(errs) == 1 {
+			return errs[0]
+		}
+		return errs.err()
+	}
+}
+
+// closeWithTimeout invokes f and waits for it to return for at most the
+// timeout. A timeout of zero means closeWithTimeout waits until f returns.
+func closeWithTimeout(f func() error, timeout time.Duration) error {
+	if timeout <= 0 {
+		return f()
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- f() }()
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case err := <-errc:
+		return err
+	case <-t.C:
+		return ErrTimeout
+	}
+}
+
+// RegisterCloser registers an exit handler that closes c. An error
+// returned by c's Close function is returned by the exit handler. Please
+// see RegisterWithPriority for a description of the priority.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterCloser(name string, c io.Closer, priority int, opts ...CloseOption) func() {
+	return m.RegisterNamedFunc(name, closeFunc(c.Close, opts), priority)
+}
+
+// RegisterCloser registers an exit handler that closes c. Please see the
+// Manager's RegisterCloser function.
+func RegisterCloser(name string, c io.Closer, priority int, opts ...CloseOption) func() {
+	return defaultManager.RegisterCloser(name, c, priority, opts...)
+}
@@ -0,0 +1,91 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// coalescer is the state used to coalesce bursts of the same signal.
+type coalescer struct {
+	// window is the amount of time after a signal is delivered during
+	// which the same signal is discarded.
+	window time.Duration
+
+	// last is the time at which each signal was last delivered to the
+	// exit handlers or the observers.
+	last map[coalesceKey]time.Time
+
+	sync.Mutex
+}
+
+// coalesceKey identifies a signal and whether it was received by the
+// observers. A signal that is both trapped and observed is received twice,
+// and each delivery is coalesced independently.
+type coalesceKey struct {
+	s        os.Signal
+	observed bool
+}
+
+// coalesced returns true if the signal was received within the window of
+// the last delivery of the same signal and should be discarded.
+func (c *coalescer) coalesced(k coalesceKey) bool {
+	c.Lock()
+	defer c.Unlock()
+	if c.window <= 0 {
+		return false
+	}
+	now := time.Now()
+	if t, ok := c.last[k]; ok && now.Sub(t) < c.window {
+		return true
+	}
+	if c.last == nil {
+		c.last = map[coalesceKey]time.Time{}
+	}
+	c.last[k] = now
+	return false
+}
+
+// SetCoalesceWindow sets the window within which repeated deliveries of
+// the same signal are coalesced. Once a trapped or observed signal is
+// delivered, the same signal received again before the window elapses is
+// discarded. This prevents a burst of identical signals, such as the
+// repeated SIGTERMs sent by an orchestrator or a retry loop, from
+// escalating the shutdown or invoking the observers more than once.
+// Different signals are not coalesced with one another. A window that is
+// less than or equal to zero, the default, disables coalescing.
+func (m *Manager) SetCoalesceWindow(window time.Duration) {
+	m.coalescer.Lock()
+	defer m.coalescer.Unlock()
+	m.coalescer.window = window
+	m.coalescer.last = nil
+}
+
+// coalesce returns true if the signal should be discarded because it is a
+// repeat of the same signal received within the coalesce window. The
+// observed argument is true if the signal was received by the observers.
+func (m *Manager) coalesce(s os.Signal, observed bool) bool {
+	if !m.coalescer.coalesced(coalesceKey{s: s, observed: observed}) {
+		return false
+	}
+	m.log(m.context(), LevelDebug, MsgSignalCoalesced, KeySignal, s)
+	return true
+}
+
+// SetCoalesceWindow sets the window within which repeated deliveries of
+// the same signal are coalesced. Please see the Manager's
+// SetCoalesceWindow function.
+func SetCoalesceWindow(window time.Duration) {
+	defaultManager.SetCoalesceWindow(window)
+}
+
+// WithCoalesceWindow returns an Option that sets the window within which
+// repeated deliveries of the same signal are coalesced. Please see the
+// SetCoalesceWindow function.
+func WithCoalesceWindow(window time.Duration) Option {
+	return func(m *Manager) {
+		m.SetCoalesceWindow(window)
+	}
+}
@@ -0,0 +1,77 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"os"
+)
+
+// RegisterOnSignal registers a function to be invoked only when this
+// process exits due to a process signal. The handler is given a priority
+// of 0.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterOnSignal(f ExitHandler) func() {
+	return m.register(&handler{
+		f:    func(ctx context.Context, s os.Signal) error { f(ctx, s); return nil },
+		when: func(s os.Signal) bool { return !IsNormalExit(s) },
+	})
+}
+
+// RegisterOnNormalExit registers a function to be invoked only when this
+// process exits normally, i.e. when the Exit function is invoked. The
+// handler is given a priority of 0.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterOnNormalExit(f ExitHandler) func() {
+	return m.register(&handler{
+		f:    func(ctx context.Context, s os.Signal) error { f(ctx, s); return nil },
+		when: IsNormalExit,
+	})
+}
+
+// RegisterForSignal registers a function to be invoked only when the
+// specified signal causes this process to exit. The handler is given a
+// priority of 0.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterForSignal(sig os.Signal, f ExitHandler) func() {
+	if !m.checkSignal(sig) {
+		return func() {}
+	}
+	return m.register(&handler{
+		f:    func(ctx context.Context, s os.Signal) error { f(ctx, s); return nil },
+		when: func(s os.Signal) bool { return s == sig },
+	})
+}
+
+// RegisterOnSignal registers a function to be invoked only when this
+// process exits due to a process signal. The handler is given a priority
+// of 0.
+//
+// The returned function removes the handler when invoked.
+func RegisterOnSignal(f ExitHandler) func() {
+	return defaultManager.RegisterOnSignal(f)
+}
+
+// RegisterOnNormalExit registers a function to be invoked only when this
+// process exits normally, i.e. when the Exit function is invoked. The
+// handler is given a priority of 0.
+//
+// The returned function removes the handler when invoked.
+func RegisterOnNormalExit(f ExitHandler) func() {
+	return defaultManager.RegisterOnNormalExit(f)
+}
+
+// RegisterForSignal registers a function to be invoked only when the
+// specified signal causes this process to exit. The handler is given a
+// priority of 0.
+//
+// The returned function removes the handler when invoked.
+func RegisterForSignal(sig os.Signal, f ExitHandler) func() {
+	return defaultManager.RegisterForSignal(sig, f)
+}
@@ -0,0 +1,168 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Config is a declarative shutdown policy. A Config may be loaded from
+// JSON with the Configure function. The struct tags also allow a Config
+// to be decoded from YAML, or any other format, by a third-party package
+// and applied with the ApplyConfig function.
+//
+// The following JSON is an example of a Config:
+//
+//	{
+//	  "signals": {"SIGTERM": 0, "SIGINT": 130},
+//	  "grace_period": "30s",
+//	  "concurrency": 4,
+//	  "phase_timeouts": {"drain": "20s", "flush": "5s", "close": "5s"}
+//	}
+type Config struct {
+	// Signals maps the names of the signals trapped by the Notify
+	// functions, when they are invoked without any signals, to the exit
+	// codes with which the process exits when the signals are received.
+	// Please see the ParseSignal function for the supported names.
+	Signals map[string]int `json:"signals,omitempty" yaml:"signals,omitempty"`
+
+	// GracePeriod is the amount of time the exit handlers are given to
+	// complete. Please see the SetGracePeriod function.
+	GracePeriod Duration `json:"grace_period,omitempty" yaml:"grace_period,omitempty"`
+
+	// ForcedExitCode is the exit code used when the grace period expires.
+	// Please see the SetForcedExitCode function.
+	ForcedExitCode *int `json:"forced_exit_code,omitempty" yaml:"forced_exit_code,omitempty"`
+
+	// ErrorExitCode is the exit code used when an exit handler returns an
+	// error. Please see the SetErrorExitCode function.
+	ErrorExitCode *int `json:"error_exit_code,omitempty" yaml:"error_exit_code,omitempty"`
+
+	// Concurrency is the maximum number of exit handlers that share a
+	// priority level that may be executed concurrently. Please see the
+	// SetConcurrency function.
+	Concurrency int `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+
+	// PhaseTimeouts maps the names of phases, or the string form of
+	// priority levels, to the amount of time their handlers are given to
+	// complete. Please see the SetPhaseTimeout function.
+	PhaseTimeouts map[string]Duration `json:"phase_timeouts,omitempty" yaml:"phase_timeouts,omitempty"`
+
+	// PreStopDelay is the amount of time to wait before executing the exit
+	// handlers. Please see the SetPreStopDelay function.
+	PreStopDelay Duration `json:"pre_stop_delay,omitempty" yaml:"pre_stop_delay,omitempty"`
+
+	// ShellExitCodes sets whether trapped signals cause the process to
+	// exit with 128 plus the signal's number. Please see the
+	// SetShellExitCodes function.
+	ShellExitCodes *bool `json:"shell_exit_codes,omitempty" yaml:"shell_exit_codes,omitempty"`
+}
+
+// Duration is a time.Duration that is encoded as a string, ex. "30s".
+type Duration time.Duration
+
+// MarshalText returns the duration as a string.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText parses the duration with the time.ParseDuration function.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Configure reads a JSON-encoded Config from the reader and applies it
+// with the ApplyConfig function.
+func (m *Manager) Configure(r io.Reader) error {
+	var c Config
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return fmt.Errorf("goodbye: invalid config: %v", err)
+	}
+	return m.ApplyConfig(c)
+}
+
+// ApplyConfig applies the Config to the Manager. The Config is validated
+// before any of its values are applied. The signals in the Config are
+// trapped by the next invocation of a Notify function without signals.
+func (m *Manager) ApplyConfig(c Config) error {
+	specs := make([]SignalSpec, 0, len(c.Signals))
+	for name, code := range c.Signals {
+		s, err := ParseSignal(name)
+		if err != nil {
+			return err
+		}
+		if err := m.validateSignal(s); err != nil {
+			return err
+		}
+		for _, spec := range specs {
+			if spec.Signal == s {
+				return &SignalError{Signal: s, Err: ErrDuplicateSignal}
+			}
+		}
+		specs = append(specs, SignalSpec{Signal: s, ExitCode: code})
+	}
+
+	timeouts := make(map[int]time.Duration, len(c.PhaseTimeouts))
+	for name, d := range c.PhaseTimeouts {
+		if p, ok := LookupPhase(name); ok {
+			timeouts[p.Priority] = time.Duration(d)
+			continue
+		}
+		p, err := strconv.Atoi(name)
+		if err != nil {
+			return fmt.Errorf("goodbye: unknown phase: %s", name)
+		}
+		timeouts[p] = time.Duration(d)
+	}
+
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	if len(specs) > 0 {
+		m.specs = specs
+	}
+	if c.GracePeriod > 0 {
+		m.gracePeriod = time.Duration(c.GracePeriod)
+	}
+	if c.ForcedExitCode != nil {
+		m.forcedExitCode = *c.ForcedExitCode
+	}
+	if c.ErrorExitCode != nil {
+		m.errorExitCode = *c.ErrorExitCode
+	}
+	if c.Concurrency != 0 {
+		m.concurrency = c.Concurrency
+	}
+	for p, d := range timeouts {
+		m.timeouts[p] = d
+	}
+	if c.PreStopDelay > 0 {
+		m.preStopDelay = time.Duration(c.PreStopDelay)
+	}
+	if c.ShellExitCodes != nil {
+		m.shellExitCodes = *c.ShellExitCodes
+	}
+	return nil
+}
+
+// Configure reads a JSON-encoded Config from the reader and applies it
+// with the ApplyConfig function.
+func Configure(r io.Reader) error {
+	return defaultManager.Configure(r)
+}
+
+// ApplyConfig applies the Config to the package-level functions. The
+// Config is validated before any of its values are applied. The signals in
+// the Config are trapped by the next invocation of a Notify function
+// without signals.
+func ApplyConfig(c Config) error {
+	return defaultManager.ApplyConfig(c)
+}
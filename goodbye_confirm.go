@@ -0,0 +1,90 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ConfirmFunc is invoked when an interrupt signal is received and the
+// shutdown requires confirmation. It returns true if the process should
+// begin exiting immediately, or false if the shutdown begins only when a
+// second interrupt signal is received within the confirmation window.
+type ConfirmFunc func(ctx context.Context, s os.Signal) bool
+
+// DefaultConfirm is the ConfirmFunc used when a confirmation window is set
+// without a ConfirmFunc. It writes "Interrupt again to quit" to stderr and
+// returns false.
+func DefaultConfirm(ctx context.Context, s os.Signal) bool {
+	fmt.Fprintln(os.Stderr, "Interrupt again to quit")
+	return false
+}
+
+// confirmation is the state of the interactive confirmation of interrupt
+// signals.
+type confirmation struct {
+	window time.Duration
+	f      ConfirmFunc
+	last   time.Time
+	sync.Mutex
+}
+
+// confirmed returns true if the interrupt signal should begin the
+// shutdown. It is true if confirmation is disabled, if the signal is the
+// second interrupt within the window, or if the ConfirmFunc returns true.
+func (c *confirmation) confirmed(ctx context.Context, s os.Signal) bool {
+	if s != os.Interrupt {
+		return true
+	}
+	c.Lock()
+	window, f := c.window, c.f
+	if window <= 0 {
+		c.Unlock()
+		return true
+	}
+	now := time.Now()
+	if !c.last.IsZero() && now.Sub(c.last) <= window {
+		c.last = time.Time{}
+		c.Unlock()
+		return true
+	}
+	c.last = now
+	c.Unlock()
+	return f(ctx, s)
+}
+
+// SetInterruptConfirmation sets the window within which a second interrupt
+// signal, ex. Ctrl-C, must be received for the process to begin exiting.
+// The first interrupt signal invokes the ConfirmFunc instead of executing
+// the exit handlers. Other trapped signals, such as SIGTERM, are not
+// affected. A window that is less than or equal to zero, the default,
+// disables confirmation. A nil ConfirmFunc means DefaultConfirm is used.
+func (m *Manager) SetInterruptConfirmation(window time.Duration, f ConfirmFunc) {
+	if f == nil {
+		f = DefaultConfirm
+	}
+	m.confirmation.Lock()
+	defer m.confirmation.Unlock()
+	m.confirmation.window, m.confirmation.f = window, f
+	m.confirmation.last = time.Time{}
+}
+
+// SetInterruptConfirmation sets the window within which a second interrupt
+// signal must be received for the process to begin exiting. Please see the
+// Manager's SetInterruptConfirmation function.
+func SetInterruptConfirmation(window time.Duration, f ConfirmFunc) {
+	defaultManager.SetInterruptConfirmation(window, f)
+}
+
+// WithInterruptConfirmation returns an Option that sets the window within
+// which a second interrupt signal must be received for the process to
+// begin exiting. Please see the SetInterruptConfirmation function.
+func WithInterruptConfirmation(window time.Duration, f ConfirmFunc) Option {
+	return func(m *Manager) {
+		m.SetInterruptConfirmation(window, f)
+	}
+}
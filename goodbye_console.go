@@ -0,0 +1,43 @@
+// +build go1.8
+
+package goodbye
+
+import "strconv"
+
+// ConsoleEvent is a Windows console control event. Console events are
+// signals that may be trapped with the Notify function, and on Windows
+// the CtrlCloseEvent, CtrlLogoffEvent, and CtrlShutdownEvent events are
+// trapped by default so that closing the console window, logging off, or
+// shutting down the computer executes the exit handlers. Windows
+// terminates the process shortly after the event is received, regardless
+// of the grace period.
+//
+// Console events are never received on other platforms.
+type ConsoleEvent uint32
+
+const (
+	// CtrlCloseEvent is received when the console window is closed.
+	CtrlCloseEvent ConsoleEvent = 2
+
+	// CtrlLogoffEvent is received by services when a user logs off.
+	CtrlLogoffEvent ConsoleEvent = 5
+
+	// CtrlShutdownEvent is received by services when the computer is
+	// shutting down.
+	CtrlShutdownEvent ConsoleEvent = 6
+)
+
+// Signal is a no-op that allows a ConsoleEvent to be an os.Signal.
+func (e ConsoleEvent) Signal() {}
+
+func (e ConsoleEvent) String() string {
+	switch e {
+	case CtrlCloseEvent:
+		return "CTRL_CLOSE_EVENT"
+	case CtrlLogoffEvent:
+		return "CTRL_LOGOFF_EVENT"
+	case CtrlShutdownEvent:
+		return "CTRL_SHUTDOWN_EVENT"
+	}
+	return "console event " + strconv.Itoa(int(e))
+}
@@ -0,0 +1,78 @@
+// +build windows
+
+package goodbye
+
+import (
+	"os"
+	"sync"
+	"syscall"
+)
+
+var procSetConsoleCtrlHandler = syscall.NewLazyDLL(
+	"kernel32.dll").NewProc("SetConsoleCtrlHandler")
+
+// console is the state of the console control handler, which is installed
+// once for the process and dispatches console events to the Managers that
+// trap them.
+var console struct {
+	once     sync.Once
+	managers map[*Manager]struct{}
+	sync.Mutex
+}
+
+// notifyEvents installs the console control handler and registers the
+// Manager with it if any of the signals are console events.
+func (m *Manager) notifyEvents(sigs map[os.Signal]int) {
+	var ok bool
+	for s := range sigs {
+		if _, ok = s.(ConsoleEvent); ok {
+			break
+		}
+	}
+	if !ok {
+		return
+	}
+
+	console.Lock()
+	if console.managers == nil {
+		console.managers = map[*Manager]struct{}{}
+	}
+	console.managers[m] = struct{}{}
+	console.Unlock()
+
+	console.once.Do(func() {
+		procSetConsoleCtrlHandler.Call(syscall.NewCallback(consoleCtrlHandler), 1)
+	})
+}
+
+// consoleCtrlHandler dispatches a console event to the Managers that trap
+// it. Windows terminates the process when the handler returns, so the
+// handler blocks until the process exits if the event is trapped. If no
+// Manager traps the event then the next handler is invoked.
+func consoleCtrlHandler(ev uintptr) uintptr {
+	s := ConsoleEvent(ev)
+
+	console.Lock()
+	managers := make([]*Manager, 0, len(console.managers))
+	for m := range console.managers {
+		managers = append(managers, m)
+	}
+	console.Unlock()
+
+	var trapped bool
+	for _, m := range managers {
+		m.configRWL.RLock()
+		sigs := m.trapped
+		m.configRWL.RUnlock()
+		if _, ok := sigs[s]; !ok {
+			continue
+		}
+		trapped = true
+		m.notifyObservers(s)
+		go m.dispatch(m.context(), s, sigs)
+	}
+	if !trapped {
+		return 0
+	}
+	select {}
+}
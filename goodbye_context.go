@@ -0,0 +1,102 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"os"
+)
+
+// Context returns a copy of the parent context that is canceled when a
+// trapped signal is received or the Exit function is invoked. The context
+// is canceled before the exit handlers are executed, allowing in-flight
+// work to be canceled as the process begins to exit.
+//
+// Invoking the returned function cancels the context and releases the
+// resources associated with it.
+func (m *Manager) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	done := m.Done()
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// Done returns a channel that is closed when a trapped signal is received
+// or the Exit function is invoked, before the exit handlers are executed.
+func (m *Manager) Done() <-chan struct{} {
+	return m.currentCycle().done
+}
+
+// IsShuttingDown returns true if a trapped signal has been received or the
+// Exit function has been invoked.
+func (m *Manager) IsShuttingDown() bool {
+	select {
+	case <-m.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait blocks until a trapped signal is received, the Exit function is
+// invoked, or the context is canceled. Wait returns the signal that was
+// received and the exit code with which the process is planned to exit.
+// If the Exit function was invoked then the signal is one for which the
+// IsNormalExit function returns true. If the context was canceled then
+// the context's error is returned.
+//
+// The exit handlers are executed concurrently with the return of Wait.
+// The process exits with the planned exit code unless a handler error or
+// an expired grace period causes it to exit with a different code.
+func (m *Manager) Wait(ctx context.Context) (os.Signal, int, error) {
+	c := m.currentCycle()
+	select {
+	case <-c.done:
+		return c.exitSig, c.exitCode, nil
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+}
+
+// Context returns a copy of the parent context that is canceled when a
+// trapped signal is received or the Exit function is invoked. The context
+// is canceled before the exit handlers are executed, allowing in-flight
+// work to be canceled as the process begins to exit.
+//
+// Invoking the returned function cancels the context and releases the
+// resources associated with it.
+func Context(parent context.Context) (context.Context, context.CancelFunc) {
+	return defaultManager.Context(parent)
+}
+
+// Done returns a channel that is closed when a trapped signal is received
+// or the Exit function is invoked, before the exit handlers are executed.
+func Done() <-chan struct{} {
+	return defaultManager.Done()
+}
+
+// IsShuttingDown returns true if a trapped signal has been received or the
+// Exit function has been invoked.
+func IsShuttingDown() bool {
+	return defaultManager.IsShuttingDown()
+}
+
+// Wait blocks until a trapped signal is received, the Exit function is
+// invoked, or the context is canceled. Wait returns the signal that was
+// received and the exit code with which the process is planned to exit.
+// If the Exit function was invoked then the signal is one for which the
+// IsNormalExit function returns true. If the context was canceled then
+// the context's error is returned.
+//
+// The exit handlers are executed concurrently with the return of Wait.
+// The process exits with the planned exit code unless a handler error or
+// an expired grace period causes it to exit with a different code.
+func Wait(ctx context.Context) (os.Signal, int, error) {
+	return defaultManager.Wait(ctx)
+}
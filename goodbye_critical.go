@@ -0,0 +1,49 @@
+// +build go1.8
+
+package goodbye
+
+import "time"
+
+// DefaultCriticalTimeout is the default amount of time the handlers of
+// PhaseCritical are given to complete.
+const DefaultCriticalTimeout = 2 * time.Second
+
+// minPriority is the lowest possible priority.
+const minPriority = -int(^uint(0)>>1) - 1
+
+// RegisterCritical registers a named function that returns an error to be
+// invoked during PhaseCritical. Critical handlers are executed first, as
+// soon as the shutdown begins, and are given DefaultCriticalTimeout to
+// complete unless a different timeout is set with SetCriticalTimeout.
+// Since Windows terminates a process a few seconds after its console is
+// closed, critical handlers should be kept short.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterCritical(name string, f ExitFunc) func() {
+	return m.RegisterNamedFunc(name, f, PhaseCritical.Priority)
+}
+
+// SetCriticalTimeout sets the amount of time the handlers of PhaseCritical
+// are given to complete. The default value is DefaultCriticalTimeout. A
+// value of zero means the critical handlers are bounded only by the grace
+// period.
+func (m *Manager) SetCriticalTimeout(d time.Duration) {
+	m.SetPhaseTimeout(PhaseCritical, d)
+}
+
+// RegisterCritical registers a named function that returns an error to be
+// invoked during PhaseCritical. Please see the Manager's RegisterCritical
+// function.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func RegisterCritical(name string, f ExitFunc) func() {
+	return defaultManager.RegisterCritical(name, f)
+}
+
+// SetCriticalTimeout sets the amount of time the handlers of PhaseCritical
+// are given to complete. The default value is DefaultCriticalTimeout.
+func SetCriticalTimeout(d time.Duration) {
+	defaultManager.SetCriticalTimeout(d)
+}
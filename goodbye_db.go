@@ -0,0 +1,24 @@
+// +build go1.8
+
+package goodbye
+
+import "database/sql"
+
+// RegisterDB registers an exit handler that closes the database's
+// connection pool. Databases are typically closed late in the shutdown,
+// ex. with the priority of PhaseClose, once the handlers that use them
+// have completed. The BeforeClose option may be used to stop issuing new
+// queries before the pool is closed. An error returned by the database's
+// Close function is returned by the exit handler, which logs it.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterDB(name string, db *sql.DB, priority int, opts ...CloseOption) func() {
+	return m.RegisterNamedFunc(name, closeFunc(db.Close, opts), priority)
+}
+
+// RegisterDB registers an exit handler that closes the database's
+// connection pool. Please see the Manager's RegisterDB function.
+func RegisterDB(name string, db *sql.DB, priority int, opts ...CloseOption) func() {
+	return defaultManager.RegisterDB(name, db, priority, opts...)
+}
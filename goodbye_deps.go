@@ -0,0 +1,210 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+)
+
+// CycleError is returned when registering a handler would create a cycle
+// in the dependencies between handlers.
+type CycleError struct {
+	// Names is the list of handler names that form the cycle. The first
+	// and last names are the same.
+	Names []string
+}
+
+// Error returns the cycle as a list of handler names.
+func (e *CycleError) Error() string {
+	return "goodbye: dependency cycle: " + strings.Join(e.Names, " -> ")
+}
+
+// RegisterAfter registers a named function to be invoked when this process
+// exits normally or due to a process signal. The handler is not executed
+// until all of the handlers named after have completed.
+//
+// The handler is executed at priority 0 or at the priority of the handlers
+// it depends on if that priority is later. Handlers that do not exist when
+// the process exits are ignored. An error of type CycleError is returned
+// if the dependency would create a cycle.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterAfter(name, after string, f ExitHandler) (func(), error) {
+	return m.RegisterAfterFunc(name, after, func(ctx context.Context, s os.Signal) error {
+		f(ctx, s)
+		return nil
+	})
+}
+
+// RegisterAfterFunc registers a named function that returns an error to be
+// invoked when this process exits normally or due to a process signal.
+// Please see RegisterAfter for a description of the after argument.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterAfterFunc(name, after string, f ExitFunc) (func(), error) {
+	h := &handler{f: f, name: name, after: []string{after}}
+
+	m.handlersRWL.Lock()
+	defer m.handlersRWL.Unlock()
+	if path := m.dependencyPath(after, name); path != nil {
+		return nil, &CycleError{Names: append([]string{name}, path...)}
+	}
+	m.add(h)
+	return func() { m.unregister(h) }, nil
+}
+
+// RegisterAfter registers a named function to be invoked when this process
+// exits normally or due to a process signal. The handler is not executed
+// until all of the handlers named after have completed.
+//
+// The handler is executed at priority 0 or at the priority of the handlers
+// it depends on if that priority is later. Handlers that do not exist when
+// the process exits are ignored. An error of type CycleError is returned
+// if the dependency would create a cycle.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func RegisterAfter(name, after string, f ExitHandler) (func(), error) {
+	return defaultManager.RegisterAfter(name, after, f)
+}
+
+// RegisterAfterFunc registers a named function that returns an error to be
+// invoked when this process exits normally or due to a process signal.
+// Please see RegisterAfter for a description of the after argument.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func RegisterAfterFunc(name, after string, f ExitFunc) (func(), error) {
+	return defaultManager.RegisterAfterFunc(name, after, f)
+}
+
+// dependencyPath returns the chain of handler names that leads from the
+// handler named from to the handler named to by following the handlers'
+// dependencies, or nil if there is no such chain. The caller must hold
+// the handlersRWL lock.
+func (m *Manager) dependencyPath(from, to string) []string {
+	if from == to {
+		return []string{to}
+	}
+	visited := map[string]bool{}
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		if visited[name] {
+			return nil
+		}
+		visited[name] = true
+		for _, a := range m.handlers {
+			for _, h := range a {
+				if h.name != name {
+					continue
+				}
+				for _, dep := range h.after {
+					if dep == to {
+						return []string{name, to}
+					}
+					if path := visit(dep); path != nil {
+						return append([]string{name}, path...)
+					}
+				}
+			}
+		}
+		return nil
+	}
+	return visit(from)
+}
+
+// group is a list of handlers that share a priority level and do not
+// depend on each other.
+type group struct {
+	// priority is the effective priority of the handlers.
+	priority int
+	handlers []*handler
+}
+
+// groups returns a snapshot of the registered handlers that apply to the
+// signal grouped in the order in which they should be executed. A snapshot
+// is used so that exit handlers may unregister themselves or other handlers
+// without deadlocking.
+func (m *Manager) groups(s os.Signal) []group {
+	m.handlersRWL.RLock()
+	var (
+		all    []*handler
+		byName = map[string][]*handler{}
+	)
+	for _, a := range m.handlers {
+		for _, h := range a {
+			if !h.applies(s) {
+				continue
+			}
+			all = append(all, h)
+			if h.name != "" {
+				byName[h.name] = append(byName[h.name], h)
+			}
+		}
+	}
+	m.handlersRWL.RUnlock()
+
+	// The effective priority of a handler is its own priority or the
+	// latest effective priority of the handlers it depends on. The depth
+	// of a handler is the number of handlers it transitively depends on
+	// within its effective priority level.
+	var (
+		priorities = map[*handler]int{}
+		depths     = map[*handler]int{}
+		resolve    func(h *handler)
+	)
+	resolve = func(h *handler) {
+		if _, ok := priorities[h]; ok {
+			return
+		}
+		p := h.priority
+		for _, name := range h.after {
+			for _, dep := range byName[name] {
+				resolve(dep)
+				if priorities[dep] > p {
+					p = priorities[dep]
+				}
+			}
+		}
+		d := 0
+		for _, name := range h.after {
+			for _, dep := range byName[name] {
+				if priorities[dep] == p && depths[dep]+1 > d {
+					d = depths[dep] + 1
+				}
+			}
+		}
+		priorities[h], depths[h] = p, d
+	}
+	for _, h := range all {
+		resolve(h)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		hi, hj := all[i], all[j]
+		if priorities[hi] != priorities[hj] {
+			return priorities[hi] < priorities[hj]
+		}
+		if depths[hi] != depths[hj] {
+			return depths[hi] < depths[hj]
+		}
+		return hi.seq < hj.seq
+	})
+
+	var groups []group
+	for i, h := range all {
+		if i == 0 ||
+			priorities[h] != priorities[all[i-1]] ||
+			depths[h] != depths[all[i-1]] {
+			groups = append(groups, group{priority: priorities[h]})
+		}
+		g := &groups[len(groups)-1]
+		g.handlers = append(g.handlers, h)
+	}
+	return groups
+}
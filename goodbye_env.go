@@ -0,0 +1,91 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// EnvSignals is the name of the environment variable that overrides
+	// the signals trapped by the Notify and NotifySignals functions. The
+	// value is parsed with the ParseSignalSpecs function.
+	EnvSignals = "GOODBYE_SIGNALS"
+
+	// EnvGracePeriod is the name of the environment variable that sets the
+	// grace period. The value is parsed with the time.ParseDuration
+	// function.
+	EnvGracePeriod = "GOODBYE_GRACE_PERIOD"
+
+	// EnvExitCode is the name of the environment variable that sets the
+	// exit code used by the Exit function if it is called with an exit
+	// code value of -1.
+	EnvExitCode = "GOODBYE_EXIT_CODE"
+)
+
+// SetUseEnv sets whether the Notify and NotifySignals functions read the
+// EnvSignals, EnvGracePeriod, and EnvExitCode environment variables. This
+// allows operators to tune the shutdown behavior of a program without
+// rebuilding it. The environment variables take precedence over the
+// signals provided to the Notify functions and the values set with the
+// SetGracePeriod function. The default value is false.
+func (m *Manager) SetUseEnv(enabled bool) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.useEnv = enabled
+}
+
+// applyEnv applies the environment variables if the Manager is configured
+// to use them. The returned specs are the ones parsed from EnvSignals or
+// the provided specs if EnvSignals is not set.
+func (m *Manager) applyEnv(specs []SignalSpec) ([]SignalSpec, error) {
+	m.configRWL.RLock()
+	useEnv := m.useEnv
+	m.configRWL.RUnlock()
+	if !useEnv {
+		return specs, nil
+	}
+
+	if v := os.Getenv(EnvSignals); v != "" {
+		s, err := ParseSignalSpecs(v)
+		if err != nil {
+			return nil, envError(EnvSignals, err)
+		}
+		specs = s
+	}
+
+	if v := os.Getenv(EnvGracePeriod); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, envError(EnvGracePeriod, err)
+		}
+		m.SetGracePeriod(d)
+	}
+
+	if v := os.Getenv(EnvExitCode); v != "" {
+		x, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, envError(EnvExitCode, err)
+		}
+		m.configRWL.Lock()
+		m.ExitCode = x
+		m.configRWL.Unlock()
+	}
+
+	return specs, nil
+}
+
+// SetUseEnv sets whether the Notify and NotifySignals functions read the
+// EnvSignals, EnvGracePeriod, and EnvExitCode environment variables. This
+// allows operators to tune the shutdown behavior of a program without
+// rebuilding it. The environment variables take precedence over the
+// signals provided to the Notify functions and the values set with the
+// SetGracePeriod function. The default value is false.
+//
+// The exit code read from EnvExitCode is used by the Exit function only
+// if the ExitCode variable is zero.
+func SetUseEnv(enabled bool) {
+	defaultManager.SetUseEnv(enabled)
+}
@@ -0,0 +1,12 @@
+//go:build go1.13
+// +build go1.13
+
+package goodbye
+
+import "fmt"
+
+// envError returns an error that wraps the error caused by the value of the
+// environment variable.
+func envError(key string, err error) error {
+	return fmt.Errorf("goodbye: %s: %w", key, err)
+}
@@ -0,0 +1,13 @@
+//go:build !go1.13
+// +build !go1.13
+
+package goodbye
+
+import "fmt"
+
+// envError returns an error that describes the error caused by the value
+// of the environment variable. The error cannot be unwrapped since the
+// fmt package does not support wrapping errors before Go 1.13.
+func envError(key string, err error) error {
+	return fmt.Errorf("goodbye: %s: %v", key, err)
+}
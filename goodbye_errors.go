@@ -0,0 +1,122 @@
+package goodbye
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	// ErrUntrappableSignal indicates a signal cannot be trapped, such as
+	// SIGKILL and SIGSTOP.
+	ErrUntrappableSignal = errors.New("goodbye: signal cannot be trapped")
+
+	// ErrDuplicateSignal indicates a signal was specified more than once.
+	ErrDuplicateSignal = errors.New("goodbye: duplicate signal")
+
+	// ErrMissingSignal indicates an exit code was specified without a
+	// preceding signal.
+	ErrMissingSignal = errors.New("goodbye: exit code without a signal")
+
+	// ErrInvalidSignal indicates a value passed to the Notify function is
+	// not an Option, os.Signal, signal name, or exit code.
+	ErrInvalidSignal = errors.New("goodbye: invalid signal")
+
+	// ErrTimeout indicates an exit handler did not complete before the
+	// timeout of its priority level expired.
+	ErrTimeout = errors.New("goodbye: timed out")
+
+	// ErrUnknownSignal indicates a signal name does not match a signal
+	// available on the operating system.
+	ErrUnknownSignal = errors.New("goodbye: unknown signal")
+
+	// ErrNotTrapped indicates a signal is not trapped.
+	ErrNotTrapped = errors.New("goodbye: signal not trapped")
+
+	// ErrAborted indicates a shutdown was aborted with the Abort function
+	// before it was committed.
+	ErrAborted = errors.New("goodbye: shutdown aborted")
+
+	// ErrShutdownStarted indicates the Shutdown function was invoked after
+	// the Manager had already begun to shut down.
+	ErrShutdownStarted = errors.New("goodbye: shutdown already started")
+
+	// ErrNotSupported indicates a feature is not supported on the
+	// operating system.
+	ErrNotSupported = errors.New("goodbye: not supported")
+)
+
+// SignalError is an error related to a specific signal.
+type SignalError struct {
+	// Signal is the signal that caused the error.
+	Signal os.Signal
+
+	// Err is one of the ErrUntrappableSignal, ErrDuplicateSignal,
+	// ErrMissingSignal, ErrInvalidSignal, ErrUnknownSignal, or
+	// ErrNotTrapped errors.
+	Err error
+}
+
+// Error returns the error's message followed by the signal.
+func (e *SignalError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Err, e.Signal)
+}
+
+// Unwrap returns the underlying error.
+func (e *SignalError) Unwrap() error {
+	return e.Err
+}
+
+// HandlerError is an error returned by an exit handler.
+type HandlerError struct {
+	// Name is the name of the handler. Handlers registered without a
+	// name have an empty name.
+	Name string
+
+	// Priority is the priority of the handler.
+	Priority int
+
+	// Err is the error returned by the handler.
+	Err error
+}
+
+// Error returns the handler's error prefixed by the handler's name, if
+// the handler has a name.
+func (e *HandlerError) Error() string {
+	if e.Name == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the error returned by the handler.
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// Errors is a list of errors returned by exit handlers.
+type Errors []error
+
+// Error returns the messages of the errors separated by newlines.
+func (e Errors) Error() string {
+	a := make([]string, len(e))
+	for i, err := range e {
+		a[i] = err.Error()
+	}
+	return strings.Join(a, "\n")
+}
+
+// Unwrap returns the list of errors. This enables the errors.Is and
+// errors.As functions to inspect the individual errors.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// err returns nil if the list is empty, otherwise the list itself.
+func (e Errors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
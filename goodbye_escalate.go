@@ -0,0 +1,74 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"os"
+	"sync"
+)
+
+// DefaultEscalationExitCode is the default exit code used when a second
+// trapped signal is received while the exit handlers are running. It
+// matches the exit code shells report for a process interrupted by SIGINT.
+const DefaultEscalationExitCode = 130
+
+// escalation holds the function that exits the process immediately when a
+// trapped signal is received during a shutdown.
+type escalation struct {
+	f func(s os.Signal)
+	sync.Mutex
+}
+
+func (e *escalation) set(f func(s os.Signal)) {
+	e.Lock()
+	defer e.Unlock()
+	e.f = f
+}
+
+func (e *escalation) get() func(s os.Signal) {
+	e.Lock()
+	defer e.Unlock()
+	return e.f
+}
+
+// SetEscalation sets whether a trapped signal that is received while the
+// exit handlers are running causes the process to exit immediately,
+// without executing the remaining handlers. The process exits with the
+// escalation exit code. It is disabled by default.
+func (m *Manager) SetEscalation(enabled bool) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.escalation = enabled
+}
+
+// SetEscalationExitCode sets the exit code used when a second trapped
+// signal causes the process to exit immediately. The default value is
+// DefaultEscalationExitCode.
+func (m *Manager) SetEscalationExitCode(exitCode int) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.escalationExitCode = exitCode
+}
+
+// SetEscalation sets whether a trapped signal that is received while the
+// exit handlers are running causes the process to exit immediately. Please
+// see the Manager's SetEscalation function.
+func SetEscalation(enabled bool) {
+	defaultManager.SetEscalation(enabled)
+}
+
+// SetEscalationExitCode sets the exit code used when a second trapped
+// signal causes the process to exit immediately. The default value is
+// DefaultEscalationExitCode.
+func SetEscalationExitCode(exitCode int) {
+	defaultManager.SetEscalationExitCode(exitCode)
+}
+
+// WithEscalation returns an Option that sets whether a second trapped
+// signal causes the process to exit immediately. Please see the
+// SetEscalation function.
+func WithEscalation(enabled bool) Option {
+	return func(m *Manager) {
+		m.SetEscalation(enabled)
+	}
+}
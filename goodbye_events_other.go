@@ -0,0 +1,9 @@
+// +build !windows,!js
+
+package goodbye
+
+import "os"
+
+// notifyEvents does nothing since console and page events are not
+// received on this platform.
+func (m *Manager) notifyEvents(sigs map[os.Signal]int) {}
@@ -0,0 +1,89 @@
+// +build go1.8
+
+package goodbye
+
+// ExitCoder is implemented by errors that specify the exit code with which
+// the process should exit. If an exit handler returns an error that is or
+// wraps an ExitCoder, and the process would otherwise exit with an exit
+// code of zero, then the code is used instead of the error exit code.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitCoderPolicy determines which exit code is used when more than one
+// exit handler returns an ExitCoder.
+type ExitCoderPolicy int
+
+const (
+	// ExitCoderHighest uses the highest exit code. It is the default.
+	ExitCoderHighest ExitCoderPolicy = iota
+
+	// ExitCoderFirst uses the exit code of the first handler that was
+	// executed.
+	ExitCoderFirst
+
+	// ExitCoderLast uses the exit code of the last handler that was
+	// executed.
+	ExitCoderLast
+)
+
+// exitCoderCode returns the exit code selected by the policy from the
+// errors that are or wrap an ExitCoder. The returned boolean is false if
+// none of the errors are an ExitCoder.
+func exitCoderCode(err error, policy ExitCoderPolicy) (int, bool) {
+	var errs []error
+	if a, ok := err.(Errors); ok {
+		errs = a
+	} else if err != nil {
+		errs = []error{err}
+	}
+
+	var (
+		code  int
+		found bool
+	)
+	for _, err := range errs {
+		c, ok := exitCodeOf(err)
+		if !ok {
+			continue
+		}
+		switch {
+		case !found,
+			policy == ExitCoderLast,
+			policy == ExitCoderHighest && c > code:
+			code = c
+		}
+		found = true
+	}
+	return code, found
+}
+
+// exitCodeOf returns the exit code of the first error in err's chain that
+// implements ExitCoder.
+func exitCodeOf(err error) (int, bool) {
+	for err != nil {
+		if ec, ok := err.(ExitCoder); ok {
+			return ec.ExitCode(), true
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return 0, false
+		}
+		err = u.Unwrap()
+	}
+	return 0, false
+}
+
+// SetExitCoderPolicy sets which exit code is used when more than one exit
+// handler returns an ExitCoder. The default value is ExitCoderHighest.
+func (m *Manager) SetExitCoderPolicy(p ExitCoderPolicy) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.exitCoderPolicy = p
+}
+
+// SetExitCoderPolicy sets which exit code is used when more than one exit
+// handler returns an ExitCoder. The default value is ExitCoderHighest.
+func SetExitCoderPolicy(p ExitCoderPolicy) {
+	defaultManager.SetExitCoderPolicy(p)
+}
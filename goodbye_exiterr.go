@@ -0,0 +1,48 @@
+// +build go1.8
+
+package goodbye
+
+import "context"
+
+// exitErrKey is the context key of the error provided to ExitErr.
+type exitErrKey struct{}
+
+// ExitErr executes all of the registered exit handlers due to an error.
+// The error is logged, recorded as the Cause of the ShutdownReport, and is
+// available to the exit handlers with the ExitError function. The process
+// exits with the code of the error if it is or wraps an ExitCoder, and
+// otherwise exits with an exit code of 1. A nil error is equivalent to
+// invoking Exit with an exit code of 0.
+func (m *Manager) ExitErr(ctx context.Context, err error, opts ...Option) {
+	if err == nil {
+		m.Exit(ctx, 0, opts...)
+		return
+	}
+	code, ok := exitCodeOf(err)
+	if !ok {
+		code = 1
+	}
+	m.exitErr(ctx, err, code, opts...)
+}
+
+// exitErr executes all of the registered exit handlers due to an error and
+// exits with the exit code.
+func (m *Manager) exitErr(ctx context.Context, err error, code int, opts ...Option) {
+	m.log(ctx, LevelError, MsgExitError, KeyError, err, KeyExitCode, code)
+	m.Exit(context.WithValue(ctx, exitErrKey{}, err), code, opts...)
+}
+
+// ExitError returns the error provided to ExitErr, or nil if the process
+// is not exiting due to ExitErr. Exit handlers may use the context they
+// are given to learn why the process is exiting.
+func ExitError(ctx context.Context) error {
+	err, _ := ctx.Value(exitErrKey{}).(error)
+	return err
+}
+
+// ExitErr executes all of the registered exit handlers due to an error.
+// Please see the Manager's ExitErr function for a description of how the
+// exit code is derived from the error.
+func ExitErr(ctx context.Context, err error, opts ...Option) {
+	defaultManager.ExitErr(ctx, err, opts...)
+}
@@ -0,0 +1,50 @@
+// +build go1.8
+
+package goodbye
+
+import "os"
+
+// ExitCodePolicy computes the exit code of the process once the exit
+// handlers have completed. The exitcode subpackage provides policies based on
+// the BSD sysexits conventions.
+type ExitCodePolicy interface {
+	// ExitCode is invoked with the signal that caused the shutdown, the
+	// error returned by the exit handlers, which is nil or of type Errors,
+	// and the exit code that was provided to Exit or bound to the signal.
+	// It returns the code with which the process exits.
+	ExitCode(s os.Signal, err error, code int) int
+}
+
+// ExitCodePolicyFunc is a function that implements ExitCodePolicy.
+type ExitCodePolicyFunc func(s os.Signal, err error, code int) int
+
+// ExitCode invokes f.
+func (f ExitCodePolicyFunc) ExitCode(s os.Signal, err error, code int) int {
+	return f(s, err, code)
+}
+
+// SetExitCodePolicy sets the policy that computes the exit code of the
+// process once the exit handlers have completed. The policy replaces the
+// use of the error exit code and the ExitCoder interface. It does not
+// affect the exit code used when the grace period expires. A nil value
+// restores the default behavior.
+func (m *Manager) SetExitCodePolicy(p ExitCodePolicy) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.exitCodePolicy = p
+}
+
+// SetExitCodePolicy sets the policy that computes the exit code of the
+// process once the exit handlers have completed. Please see the Manager's
+// SetExitCodePolicy function.
+func SetExitCodePolicy(p ExitCodePolicy) {
+	defaultManager.SetExitCodePolicy(p)
+}
+
+// WithExitCodePolicy returns an Option that sets the policy that computes
+// the exit code of the process. Please see the SetExitCodePolicy function.
+func WithExitCodePolicy(p ExitCodePolicy) Option {
+	return func(m *Manager) {
+		m.SetExitCodePolicy(p)
+	}
+}
@@ -0,0 +1,54 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Fatal is a replacement for log.Fatal that executes all of the registered
+// exit handlers before the process exits. The error is logged with the
+// Manager's Logger, or written to the standard logger if the Manager does
+// not have a Logger, and is then handled as it is by ExitErr, except that
+// the process always exits with a non-zero exit code. The exit code is
+// that of the error if it is or wraps an ExitCoder with a non-zero code,
+// and is 1 otherwise.
+//
+// Unlike log.Fatal, which calls os.Exit and bypasses the exit handlers,
+// Fatal only returns if the function set with SetExiter returns.
+func (m *Manager) Fatal(ctx context.Context, err error, opts ...Option) {
+	m.fatal(ctx, 3, err, opts)
+}
+
+// Fatalf is equivalent to Fatal with an error formatted by fmt.Errorf.
+func (m *Manager) Fatalf(ctx context.Context, format string, args ...interface{}) {
+	m.fatal(ctx, 3, fmt.Errorf(format, args...), nil)
+}
+
+// fatal executes all of the registered exit handlers due to a fatal error.
+// The calldepth is used to report the caller's file and line number when
+// the error is written to the standard logger.
+func (m *Manager) fatal(ctx context.Context, calldepth int, err error, opts []Option) {
+	code, ok := exitCodeOf(err)
+	if !ok || code == 0 {
+		code = 1
+	}
+	if !m.hasLogger() {
+		log.Output(calldepth, fmt.Sprint(err))
+	}
+	m.exitErr(ctx, err, code, opts...)
+}
+
+// Fatal is a replacement for log.Fatal that executes all of the registered
+// exit handlers before the process exits. Please see the Manager's Fatal
+// function.
+func Fatal(ctx context.Context, err error, opts ...Option) {
+	defaultManager.fatal(ctx, 3, err, opts)
+}
+
+// Fatalf is equivalent to Fatal with an error formatted by fmt.Errorf.
+func Fatalf(ctx context.Context, format string, args ...interface{}) {
+	defaultManager.fatal(ctx, 3, fmt.Errorf(format, args...), nil)
+}
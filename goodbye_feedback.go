@@ -0,0 +1,142 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// FeedbackEvent identifies the moment at which a FeedbackFunc is invoked.
+type FeedbackEvent int
+
+const (
+	// FeedbackShutdown indicates the process has begun exiting.
+	FeedbackShutdown FeedbackEvent = iota
+
+	// FeedbackConfirm indicates an interrupt signal was received and a
+	// second interrupt signal is required to begin exiting. Please see the
+	// SetInterruptConfirmation function.
+	FeedbackConfirm
+
+	// FeedbackEscalated indicates a second trapped signal was received
+	// while the exit handlers were running and the process is exiting
+	// immediately. Please see the SetEscalation function.
+	FeedbackEscalated
+
+	// FeedbackVetoed indicates a trapped signal was received and a
+	// preparer vetoed the shutdown or the shutdown was aborted. Please see
+	// the RegisterPreparer and Abort functions.
+	FeedbackVetoed
+)
+
+// String returns the name of the event.
+func (e FeedbackEvent) String() string {
+	switch e {
+	case FeedbackShutdown:
+		return "shutdown"
+	case FeedbackConfirm:
+		return "confirm"
+	case FeedbackEscalated:
+		return "escalated"
+	case FeedbackVetoed:
+		return "vetoed"
+	}
+	return "unknown"
+}
+
+// Feedback describes a change in the state of a shutdown that may be
+// reported to the user.
+type Feedback struct {
+	// Event is the change in the state of the shutdown.
+	Event FeedbackEvent
+
+	// Signal is the signal that caused the event. It is the value returned
+	// by NormalExitSignal if the shutdown was caused by Exit.
+	Signal os.Signal
+
+	// ExitCode is the exit code with which the process is planned to
+	// exit. It is zero for FeedbackConfirm and FeedbackVetoed events.
+	ExitCode int
+
+	// Escalation is true if a second trapped signal causes the process to
+	// exit immediately.
+	Escalation bool
+
+	// Err is the *VetoError with which the shutdown was vetoed, or
+	// ErrAborted if it was aborted. It is nil for events other than
+	// FeedbackVetoed.
+	Err error
+}
+
+// FeedbackFunc is invoked with user feedback. It is invoked at most once
+// for each event and should return quickly.
+type FeedbackFunc func(ctx context.Context, fb Feedback)
+
+// DefaultFeedback is a FeedbackFunc that writes a message to stderr for
+// each event, ex. "Shutting down, press Ctrl-C again to force quit". It
+// does not write a message when the process exits normally.
+func DefaultFeedback(ctx context.Context, fb Feedback) {
+	switch fb.Event {
+	case FeedbackShutdown:
+		if IsNormalExit(fb.Signal) {
+			return
+		}
+		if fb.Escalation {
+			fmt.Fprintln(os.Stderr, "Shutting down, press Ctrl-C again to force quit")
+			return
+		}
+		fmt.Fprintln(os.Stderr, "Shutting down")
+	case FeedbackConfirm:
+		fmt.Fprintln(os.Stderr, "Interrupt again to quit")
+	case FeedbackEscalated:
+		fmt.Fprintln(os.Stderr, "Forcing quit")
+	case FeedbackVetoed:
+		if ve, ok := fb.Err.(*VetoError); ok {
+			fmt.Fprintf(os.Stderr, "Not shutting down: %s: %v\n", ve.Name, ve.Err)
+			return
+		}
+		fmt.Fprintln(os.Stderr, "Not shutting down")
+	}
+}
+
+// SetFeedback sets a function that is invoked when the process begins
+// exiting, when an interrupt signal requires confirmation, when the
+// shutdown is escalated, and when it is vetoed. CLIs and TUIs may use it
+// to print consistent guidance to the user. A nil value, the default,
+// disables feedback.
+//
+// Because DefaultConfirm also writes a message, a FeedbackFunc that reports
+// FeedbackConfirm events should be paired with a ConfirmFunc that does not.
+func (m *Manager) SetFeedback(f FeedbackFunc) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.feedback = f
+}
+
+// feedbackf invokes the FeedbackFunc, if any.
+func (m *Manager) feedbackf(ctx context.Context, fb Feedback) {
+	m.configRWL.RLock()
+	f := m.feedback
+	m.configRWL.RUnlock()
+	if f != nil {
+		f(ctx, fb)
+	}
+}
+
+// SetFeedback sets a function that is invoked when the process begins
+// exiting, when an interrupt signal requires confirmation, when the
+// shutdown is escalated, and when it is vetoed. Please see the Manager's
+// SetFeedback function.
+func SetFeedback(f FeedbackFunc) {
+	defaultManager.SetFeedback(f)
+}
+
+// WithFeedback returns an Option that sets the function that is invoked
+// with user feedback. Please see the SetFeedback function.
+func WithFeedback(f FeedbackFunc) Option {
+	return func(m *Manager) {
+		m.SetFeedback(f)
+	}
+}
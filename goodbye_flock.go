@@ -0,0 +1,31 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"os"
+)
+
+// RegisterFlock registers an exit handler that releases the advisory lock
+// held on the file, ex. with flock(2) or LockFileEx, and then closes the
+// file. The handler is given the priority of PhaseClose.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterFlock(f *os.File) func() {
+	return m.RegisterNamedFunc("flock "+f.Name(), func(ctx context.Context, s os.Signal) error {
+		uerr := unlockFile(f)
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return uerr
+	}, PhaseClose.Priority)
+}
+
+// RegisterFlock registers an exit handler that releases the advisory lock
+// held on the file and closes it. Please see the Manager's RegisterFlock
+// function.
+func RegisterFlock(f *os.File) func() {
+	return defaultManager.RegisterFlock(f)
+}
@@ -0,0 +1,13 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package goodbye
+
+import (
+	"os"
+	"syscall"
+)
+
+// unlockFile releases a lock acquired with flock(2).
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,27 @@
+// +build windows
+
+package goodbye
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var procUnlockFileEx = syscall.NewLazyDLL("kernel32.dll").NewProc("UnlockFileEx")
+
+// errorNotLocked is the ERROR_NOT_LOCKED error code returned when the file
+// is not locked.
+const errorNotLocked syscall.Errno = 158
+
+// unlockFile releases a lock acquired with LockFileEx on the entire file.
+func unlockFile(f *os.File) error {
+	var ol syscall.Overlapped
+	r, _, err := procUnlockFileEx.Call(
+		f.Fd(), 0, uintptr(^uint32(0)), uintptr(^uint32(0)),
+		uintptr(unsafe.Pointer(&ol)))
+	if r == 0 && err != errorNotLocked {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// +build go1.8
+
+package goodbye
+
+import "os"
+
+// Flusher is implemented by types that buffer data, such as *bufio.Writer.
+type Flusher interface {
+	Flush() error
+}
+
+// RegisterFlusher registers an exit handler that flushes f, ex. a
+// *bufio.Writer, with the priority of PhaseFlush so that buffered data is
+// written after the handlers that produce it have completed. The BeforeClose
+// and CloseTimeout options apply to the flush.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterFlusher(name string, f Flusher, opts ...CloseOption) func() {
+	return m.RegisterNamedFunc(name, closeFunc(f.Flush, opts), PhaseFlush.Priority)
+}
+
+// RegisterFile registers an exit handler that commits the contents of the
+// file to stable storage with its Sync function and then closes it. The
+// handler is given the priority of PhaseClose so that the file is closed
+// after buffered data has been flushed to it. The file is closed even if
+// Sync returns an error.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterFile(name string, f *os.File, opts ...CloseOption) func() {
+	return m.RegisterNamedFunc(name, closeFunc(func() error {
+		serr := f.Sync()
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return serr
+	}, opts), PhaseClose.Priority)
+}
+
+// RegisterFlusher registers an exit handler that flushes f. Please see the
+// Manager's RegisterFlusher function.
+func RegisterFlusher(name string, f Flusher, opts ...CloseOption) func() {
+	return defaultManager.RegisterFlusher(name, f, opts...)
+}
+
+// RegisterFile registers an exit handler that syncs and closes the file.
+// Please see the Manager's RegisterFile function.
+func RegisterFile(name string, f *os.File, opts ...CloseOption) func() {
+	return defaultManager.RegisterFile(name, f, opts...)
+}
@@ -0,0 +1,315 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// handle executes the handlers in the critical tier if critical is true,
+// or the remaining handlers if it is false.
+func (m *Manager) handle(ctx context.Context, s os.Signal, rec *recorder, critical bool) Errors {
+	m.configRWL.RLock()
+	concurrency := m.concurrency
+	timeouts := make(map[int]time.Duration, len(m.timeouts))
+	for k, v := range m.timeouts {
+		timeouts[k] = v
+	}
+	m.configRWL.RUnlock()
+
+	var (
+		errs     Errors
+		priority int
+		started  bool
+		expired  bool
+		drained  bool
+		timeout  <-chan time.Time
+		levelCtx = ctx
+		cancels  []context.CancelFunc
+	)
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+	for _, g := range m.groups(s) {
+		if (g.priority == PhaseCritical.Priority) != critical {
+			continue
+		}
+
+		// Wait for in-flight work once the handlers that stop accepting
+		// new work have been executed.
+		if !drained && g.priority > PhaseDrain.Priority {
+			m.waitWork(ctx)
+			drained = true
+		}
+		rec.schedule(g.handlers)
+
+		// A priority level may consist of more than one group of handlers
+		// if the handlers depend on each other. The level's timeout starts
+		// when its first group begins executing.
+		if p := g.priority; !started || p != priority {
+			priority, started, expired, timeout, levelCtx = p, true, false, nil, ctx
+			if d, ok := timeouts[p]; ok && d > 0 {
+				timeout = time.After(d)
+
+				// The context provided to the level's handlers expires
+				// with the level's timeout.
+				var cancel context.CancelFunc
+				levelCtx, cancel = context.WithTimeout(ctx, d)
+				cancels = append(cancels, cancel)
+			}
+		}
+
+		// Handlers are not executed once a panic has aborted the shutdown.
+		if rec.isAborted() {
+			continue
+		}
+
+		// Handlers in a level whose timeout has expired are not executed.
+		if expired {
+			errs = append(errs, m.timeoutErrors(ctx, rec, g.handlers)...)
+			continue
+		}
+
+		var groupErrs Errors
+		groupErrs, expired = m.runGroup(levelCtx, s, rec, g.handlers, concurrency, timeout)
+		errs = append(errs, groupErrs...)
+		if expired {
+			d := timeouts[priority]
+			m.log(ctx, LevelWarn, MsgLevelTimedOut, KeyPriority, priority, KeyDuration, d)
+			rec.overrun(priority, d)
+		}
+	}
+	if !drained && !critical {
+		m.waitWork(ctx)
+	}
+	return errs
+}
+
+// runGroup executes a group of handlers that share a priority level. The
+// returned errors are in the order in which the handlers were registered,
+// regardless of whether the handlers were executed concurrently.
+//
+// If the timeout channel receives a value before the handlers complete
+// then runGroup returns without waiting for the remaining handlers, and
+// the handlers that did not complete are given an error that wraps
+// ErrTimeout. The returned boolean is true if the timeout expired.
+func (m *Manager) runGroup(ctx context.Context, s os.Signal, rec *recorder, handlers []*handler, concurrency int, timeout <-chan time.Time) (Errors, bool) {
+	var errs Errors
+
+	sequential := concurrency == 0 || concurrency == 1 || len(handlers) == 1
+	if sequential && timeout == nil {
+		for _, h := range handlers {
+			if rec.isAborted() {
+				break
+			}
+			if err := m.call(ctx, s, rec, h); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errs, false
+	}
+
+	type result struct {
+		i   int
+		err error
+	}
+
+	var (
+		sem     chan struct{}
+		stop    = make(chan struct{})
+		resultc = make(chan result, len(handlers))
+		results = make([]error, len(handlers))
+		done    = make([]bool, len(handlers))
+	)
+	if !sequential && concurrency > 0 {
+		sem = make(chan struct{}, concurrency)
+	}
+	defer close(stop)
+
+	// Start the handlers from a separate goroutine so that the timeout
+	// may expire while waiting to start a handler. No more handlers are
+	// started once stop is closed, and handlers that are not started
+	// because a panic aborted the shutdown are reported as completing
+	// without an error.
+	go func() {
+		for i, h := range handlers {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if rec.isAborted() {
+				resultc <- result{i, nil}
+				continue
+			}
+			if sequential {
+				resultc <- result{i, m.call(ctx, s, rec, h)}
+				continue
+			}
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+				case <-stop:
+					return
+				}
+				if rec.isAborted() {
+					<-sem
+					resultc <- result{i, nil}
+					continue
+				}
+			}
+			go func(i int, h *handler) {
+				if sem != nil {
+					defer func() { <-sem }()
+				}
+				resultc <- result{i, m.call(ctx, s, rec, h)}
+			}(i, h)
+		}
+	}()
+
+	expired := false
+	for n := 0; n < len(handlers) && !expired; {
+		select {
+		case r := <-resultc:
+			results[r.i], done[r.i] = r.err, true
+			n++
+		case <-timeout:
+			expired = true
+		}
+	}
+
+	for i, err := range results {
+		if !done[i] {
+			err = m.timeoutErrors(ctx, rec, handlers[i:i+1])[0]
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs, expired
+}
+
+// timeoutErrors returns an error that wraps ErrTimeout for each of the
+// handlers.
+func (m *Manager) timeoutErrors(ctx context.Context, rec *recorder, handlers []*handler) Errors {
+	errs := make(Errors, len(handlers))
+	for i, h := range handlers {
+		m.log(ctx, LevelWarn, MsgHandlerTimedOut,
+			KeyHandler, h.String(), KeyPriority, h.priority)
+		errs[i] = &HandlerError{Name: h.name, Priority: h.priority, Err: ErrTimeout}
+		rec.abandon(h, errs[i])
+	}
+	return errs
+}
+
+// call executes a handler unless it is an optional handler that is
+// skipped. A non-nil error returned by the handler is wrapped with a
+// HandlerError, as is a PanicError if the handler panics. If the
+// PanicPolicy is PanicRepanic then call panics again with the original
+// value after the panic is recorded.
+func (m *Manager) call(ctx context.Context, s os.Signal, rec *recorder, h *handler) error {
+	if m.skipOptional(ctx, h) {
+		m.log(ctx, LevelInfo, MsgHandlerSkipped,
+			KeyHandler, h.String(), KeyPriority, h.priority)
+		rec.skip(h)
+		return nil
+	}
+
+	m.runningLock.Lock()
+	m.running[h] = struct{}{}
+	m.runningLock.Unlock()
+
+	defer func() {
+		m.runningLock.Lock()
+		delete(m.running, h)
+		m.runningLock.Unlock()
+	}()
+
+	m.log(ctx, LevelDebug, MsgHandlerStarted,
+		KeyHandler, h.String(), KeyPriority, h.priority)
+	for _, f := range rec.hooks.beforeEach {
+		f(ctx, HandlerInfo{Name: h.name, Priority: h.priority})
+	}
+	start := rec.begin(h)
+	defer m.watchSlow(ctx, h)()
+
+	if err := invoke(ctx, s, h); err != nil {
+		d := time.Since(start)
+		m.log(ctx, LevelError, MsgHandlerFailed,
+			KeyHandler, h.String(), KeyPriority, h.priority,
+			KeyDuration, d, KeyError, err)
+		herr := &HandlerError{Name: h.name, Priority: h.priority, Err: err}
+		rep := rec.finish(h, d, herr)
+		for _, f := range rec.hooks.afterEach {
+			f(ctx, rep)
+		}
+		if pe, ok := err.(*PanicError); ok && rec.policy == PanicRepanic {
+			panic(pe.Value)
+		}
+		return herr
+	}
+
+	d := time.Since(start)
+	m.log(ctx, LevelDebug, MsgHandlerFinished,
+		KeyHandler, h.String(), KeyPriority, h.priority,
+		KeyDuration, d)
+	rep := rec.finish(h, d, nil)
+	for _, f := range rec.hooks.afterEach {
+		f(ctx, rep)
+	}
+	return nil
+}
+
+// watchSlow starts a timer that logs MsgHandlerSlow if the handler is
+// still running once the slow handler threshold elapses. The returned
+// function stops the timer.
+func (m *Manager) watchSlow(ctx context.Context, h *handler) func() {
+	m.configRWL.RLock()
+	d := m.slowThreshold
+	m.configRWL.RUnlock()
+	if d <= 0 {
+		return func() {}
+	}
+	t := time.AfterFunc(d, func() {
+		m.log(ctx, LevelWarn, MsgHandlerSlow,
+			KeyHandler, h.String(), KeyPriority, h.priority, KeyDuration, d)
+	})
+	return func() { t.Stop() }
+}
+
+// reportRunning logs the names of the handlers that are still running.
+// If the Manager does not have a Logger then the names are written to
+// stderr. It is invoked when the grace period expires, and also writes the
+// grace period's stack dump if sd is not nil.
+func (m *Manager) reportRunning(ctx context.Context, s os.Signal, gracePeriod time.Duration, sd *stackDump) {
+	m.runningLock.Lock()
+	names := make([]string, 0, len(m.running))
+	for h := range m.running {
+		names = append(names, h.String())
+	}
+	m.runningLock.Unlock()
+
+	sort.Strings(names)
+	msg := fmt.Sprintf(
+		"goodbye: grace period of %s expired with handlers still running: %s",
+		gracePeriod, strings.Join(names, ", "))
+	// The message is not written to stderr if it is written there as the
+	// header of the stack dump.
+	toStderr := sd != nil && sd.path == "" && sd.w == os.Stderr
+	if m.hasLogger() {
+		m.log(ctx, LevelError, MsgGracePeriodExpired,
+			KeyGracePeriod, gracePeriod, KeyRunning, names)
+	} else if !toStderr {
+		fmt.Fprintln(os.Stderr, msg)
+	}
+	if sd != nil {
+		m.writeStackDump(ctx, s, sd, msg)
+	}
+}
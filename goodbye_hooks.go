@@ -0,0 +1,101 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"os"
+)
+
+// HandlerInfo describes an exit handler that is about to be executed.
+type HandlerInfo struct {
+	// Name is the name of the handler, or an empty string if the handler
+	// is unnamed.
+	Name string
+
+	// Priority is the priority at which the handler is executed.
+	Priority int
+}
+
+// hooks are the functions invoked around the execution of the exit
+// handlers.
+type hooks struct {
+	beforeAll  []func(ctx context.Context, s os.Signal)
+	afterAll   []func(ctx context.Context, r ShutdownReport)
+	beforeEach []func(ctx context.Context, h HandlerInfo)
+	afterEach  []func(ctx context.Context, r HandlerReport)
+}
+
+// copy returns a copy of the hooks so that they may be invoked without
+// holding the Manager's lock.
+func (h hooks) copy() hooks {
+	return hooks{
+		beforeAll:  append([]func(context.Context, os.Signal){}, h.beforeAll...),
+		afterAll:   append([]func(context.Context, ShutdownReport){}, h.afterAll...),
+		beforeEach: append([]func(context.Context, HandlerInfo){}, h.beforeEach...),
+		afterEach:  append([]func(context.Context, HandlerReport){}, h.afterEach...),
+	}
+}
+
+// BeforeAll adds a function that is invoked with the signal that caused
+// the shutdown before any non-critical exit handlers are executed. The
+// handlers registered with RegisterCritical are executed before the
+// BeforeAll functions. Please see PhaseCritical.
+func (m *Manager) BeforeAll(f func(ctx context.Context, s os.Signal)) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.hooks.beforeAll = append(m.hooks.beforeAll, f)
+}
+
+// AfterAll adds a function that is invoked with the ShutdownReport after
+// all of the exit handlers have completed. AfterAll functions are not
+// invoked if the grace period expires.
+func (m *Manager) AfterAll(f func(ctx context.Context, r ShutdownReport)) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.hooks.afterAll = append(m.hooks.afterAll, f)
+}
+
+// BeforeEach adds a function that is invoked before each exit handler is
+// executed. BeforeEach functions may be invoked concurrently if the
+// Manager's concurrency allows handlers to be executed concurrently.
+func (m *Manager) BeforeEach(f func(ctx context.Context, h HandlerInfo)) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.hooks.beforeEach = append(m.hooks.beforeEach, f)
+}
+
+// AfterEach adds a function that is invoked with a HandlerReport after
+// each exit handler returns. AfterEach functions are not invoked for
+// handlers that are skipped or that are still running when their timeout
+// expires.
+func (m *Manager) AfterEach(f func(ctx context.Context, r HandlerReport)) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.hooks.afterEach = append(m.hooks.afterEach, f)
+}
+
+// BeforeAll adds a function that is invoked with the signal that caused
+// the shutdown before any non-critical exit handlers are executed. Please
+// see the Manager's BeforeAll function.
+func BeforeAll(f func(ctx context.Context, s os.Signal)) {
+	defaultManager.BeforeAll(f)
+}
+
+// AfterAll adds a function that is invoked with the ShutdownReport after
+// all of the exit handlers have completed.
+func AfterAll(f func(ctx context.Context, r ShutdownReport)) {
+	defaultManager.AfterAll(f)
+}
+
+// BeforeEach adds a function that is invoked before each exit handler is
+// executed.
+func BeforeEach(f func(ctx context.Context, h HandlerInfo)) {
+	defaultManager.BeforeEach(f)
+}
+
+// AfterEach adds a function that is invoked with a HandlerReport after
+// each exit handler returns.
+func AfterEach(f func(ctx context.Context, r HandlerReport)) {
+	defaultManager.AfterEach(f)
+}
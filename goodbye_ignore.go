@@ -0,0 +1,86 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"os"
+	"os/signal"
+)
+
+// Ignore causes the specified signals to be ignored, which declares that
+// they have no effect on the process. An ignored signal is no longer
+// trapped, and it is not delivered to observers until it is observed or
+// trapped again, which stops ignoring it.
+//
+// The Reset function stops ignoring the signals, which restores the
+// disposition they had before Ignore was invoked. The ignored signals are
+// reported by the State function. Please note that the Go runtime
+// continues to ignore SIGHUP and SIGINT after Reset unless they are
+// trapped or observed again.
+func (m *Manager) Ignore(signals ...os.Signal) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.configRWL.RLock()
+	ctx := m.ctx
+	sigs := map[os.Signal]int{}
+	for s, x := range m.trapped {
+		sigs[s] = x
+	}
+	m.configRWL.RUnlock()
+
+	m.configRWL.Lock()
+	if m.ignored == nil {
+		m.ignored = map[os.Signal]bool{}
+	}
+	for _, s := range signals {
+		delete(sigs, s)
+		if _, ok := m.ignored[s]; !ok {
+			m.ignored[s] = signalIgnored(s)
+		}
+	}
+	m.configRWL.Unlock()
+
+	m.trap(ctx, sigs)
+	signal.Ignore(signals...)
+}
+
+// unignore stops tracking the signals as ignored since they are trapped or
+// observed again.
+func (m *Manager) unignore(signals ...os.Signal) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	for _, s := range signals {
+		delete(m.ignored, s)
+	}
+}
+
+// resetIgnored stops ignoring the signals that were not ignored before the
+// Ignore function was invoked.
+func (m *Manager) resetIgnored() {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	var sigs []os.Signal
+	for s, prev := range m.ignored {
+		if !prev {
+			sigs = append(sigs, s)
+		}
+	}
+	m.ignored = nil
+	if len(sigs) == 0 {
+		return
+	}
+
+	// The signal.Reset function does not stop ignoring a signal, but
+	// signal.Notify does, and the signal.Stop function then restores its
+	// default behavior.
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, sigs...)
+	signal.Stop(c)
+}
+
+// Ignore causes the specified signals to be ignored. Please see the
+// Manager's Ignore function.
+func Ignore(signals ...os.Signal) {
+	defaultManager.Ignore(signals...)
+}
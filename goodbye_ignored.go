@@ -0,0 +1,14 @@
+//go:build go1.11
+// +build go1.11
+
+package goodbye
+
+import (
+	"os"
+	"os/signal"
+)
+
+// signalIgnored returns true if the signal is currently ignored.
+func signalIgnored(s os.Signal) bool {
+	return signal.Ignored(s)
+}
@@ -0,0 +1,12 @@
+//go:build !go1.11
+// +build !go1.11
+
+package goodbye
+
+import "os"
+
+// signalIgnored returns false since the os/signal package cannot report
+// whether a signal is ignored before Go 1.11.
+func signalIgnored(s os.Signal) bool {
+	return false
+}
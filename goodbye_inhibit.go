@@ -0,0 +1,123 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// inhibitors are the reasons the start of the exit handlers is delayed.
+type inhibitors struct {
+	reasons map[*int]string
+	changed chan struct{}
+	sync.Mutex
+}
+
+func (in *inhibitors) add(reason string) func() {
+	in.Lock()
+	defer in.Unlock()
+	if in.reasons == nil {
+		in.reasons = map[*int]string{}
+		in.changed = make(chan struct{})
+	}
+	key := new(int)
+	in.reasons[key] = reason
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			in.Lock()
+			defer in.Unlock()
+			delete(in.reasons, key)
+			close(in.changed)
+			in.changed = make(chan struct{})
+		})
+	}
+}
+
+// list returns the sorted reasons and a channel that is closed when an
+// inhibitor is released.
+func (in *inhibitors) list() ([]string, <-chan struct{}) {
+	in.Lock()
+	defer in.Unlock()
+	reasons := make([]string, 0, len(in.reasons))
+	for _, r := range in.reasons {
+		reasons = append(reasons, r)
+	}
+	sort.Strings(reasons)
+	return reasons, in.changed
+}
+
+// Inhibit delays the execution of the exit handlers until the returned
+// function is invoked. Code that performs an operation that must not be
+// interrupted, such as writing a checkpoint, may use Inhibit to prevent
+// the handlers from closing the resources the operation depends on. The
+// reason is logged while the shutdown is delayed.
+//
+// The delay is bounded by the maximum inhibit duration and the grace
+// period. It is safe to invoke the returned function more than once.
+func (m *Manager) Inhibit(reason string) func() {
+	return m.inhibitors.add(reason)
+}
+
+// SetMaxInhibit sets the maximum amount of time that Inhibit may delay the
+// execution of the exit handlers. A value of zero, the default, means the
+// delay is bounded only by the grace period.
+func (m *Manager) SetMaxInhibit(d time.Duration) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.maxInhibit = d
+}
+
+// waitInhibitors blocks until all of the inhibitors are released, the
+// maximum inhibit duration expires, or the context is done. The context is
+// the one provided to the exit handlers, which expires with the grace
+// period.
+func (m *Manager) waitInhibitors(ctx context.Context) {
+	reasons, changed := m.inhibitors.list()
+	if len(reasons) == 0 {
+		return
+	}
+
+	m.configRWL.RLock()
+	max := m.maxInhibit
+	m.configRWL.RUnlock()
+
+	var timeout <-chan time.Time
+	if max > 0 {
+		t := time.NewTimer(max)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	start := time.Now()
+	m.log(ctx, LevelInfo, MsgShutdownInhibited, KeyInhibitors, reasons)
+	for len(reasons) > 0 {
+		select {
+		case <-changed:
+			reasons, changed = m.inhibitors.list()
+			continue
+		case <-timeout:
+		case <-ctx.Done():
+		}
+		m.log(ctx, LevelWarn, MsgInhibitTimedOut,
+			KeyInhibitors, reasons, KeyDuration, time.Since(start))
+		return
+	}
+}
+
+// Inhibit delays the execution of the exit handlers until the returned
+// function is invoked. Please see the Manager's Inhibit function.
+func Inhibit(reason string) func() {
+	return defaultManager.Inhibit(reason)
+}
+
+// SetMaxInhibit sets the maximum amount of time that Inhibit may delay the
+// execution of the exit handlers. A value of zero, the default, means the
+// delay is bounded only by the grace period.
+func SetMaxInhibit(d time.Duration) {
+	defaultManager.SetMaxInhibit(d)
+}
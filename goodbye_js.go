@@ -0,0 +1,100 @@
+// +build js,wasm
+
+package goodbye
+
+import (
+	"os"
+	"sync"
+	"syscall"
+	"syscall/js"
+)
+
+func init() {
+	defaultSignals = map[os.Signal]int{
+		PageBeforeUnload: 0,
+		PageHide:         0,
+	}
+	untrappableSignals = map[os.Signal]bool{
+		syscall.SIGKILL: true,
+	}
+	signalNames = map[string]os.Signal{
+		"SIGINT":  syscall.SIGINT,
+		"SIGKILL": syscall.SIGKILL,
+		"SIGQUIT": syscall.SIGQUIT,
+		"SIGTERM": syscall.SIGTERM,
+
+		"beforeunload":     PageBeforeUnload,
+		"pagehide":         PageHide,
+		"visibilitychange": PageHidden,
+	}
+}
+
+// page is the state of the event listeners, which are added once for each
+// page event and dispatch the event to the Managers that trap it.
+var page struct {
+	listening map[PageEvent]js.Func
+	managers  map[*Manager]struct{}
+	sync.Mutex
+}
+
+// notifyEvents adds an event listener for each of the signals that is a
+// page event and registers the Manager with the listeners.
+func (m *Manager) notifyEvents(sigs map[os.Signal]int) {
+	page.Lock()
+	defer page.Unlock()
+	for s := range sigs {
+		ev, ok := s.(PageEvent)
+		if !ok {
+			continue
+		}
+		if page.managers == nil {
+			page.managers = map[*Manager]struct{}{}
+			page.listening = map[PageEvent]js.Func{}
+		}
+		page.managers[m] = struct{}{}
+		if _, ok := page.listening[ev]; ok {
+			continue
+		}
+		f := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			pageEventHandler(ev)
+			return nil
+		})
+		page.listening[ev] = f
+		target := js.Global()
+		if ev == PageHidden {
+			target = js.Global().Get("document")
+		}
+		target.Call("addEventListener", string(ev), f)
+	}
+}
+
+// pageEventHandler dispatches a page event to the Managers that trap it.
+// The exit handlers are executed synchronously since the browser does not
+// wait for the event loop before unloading the page, so exit handlers
+// must not block on JavaScript callbacks such as the fetch API.
+func pageEventHandler(ev PageEvent) {
+	if ev == PageHidden {
+		state := js.Global().Get("document").Get("visibilityState")
+		if state.String() != "hidden" {
+			return
+		}
+	}
+
+	page.Lock()
+	managers := make([]*Manager, 0, len(page.managers))
+	for m := range page.managers {
+		managers = append(managers, m)
+	}
+	page.Unlock()
+
+	for _, m := range managers {
+		m.configRWL.RLock()
+		sigs := m.trapped
+		m.configRWL.RUnlock()
+		if _, ok := sigs[ev]; !ok {
+			continue
+		}
+		m.notifyObservers(ev)
+		m.dispatch(m.context(), ev, sigs)
+	}
+}
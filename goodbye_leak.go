@@ -0,0 +1,154 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// leakSettleTime is how long the leak detector waits for goroutines that
+// are still exiting before it reports them as leaked.
+const leakSettleTime = time.Second
+
+// ignoredLeaks are the functions whose goroutines are started by this
+// package or the os/signal package and are never reported as leaked.
+var ignoredLeaks = []string{
+	"goodbye.(*Manager).dispatcher(",
+	"goodbye.(*Manager).dispatch(",
+	"goodbye.(*Manager).observe(",
+	"os/signal.loop(",
+	"os/signal.signal_recv(",
+	"runtime.ensureSigM",
+}
+
+// SetLeakDetection sets whether goroutines that are leaked by the program
+// are reported when it exits. It is intended for tests and staging
+// environments and is disabled by default.
+//
+// Enabling leak detection records the goroutines that are running at the
+// time as the baseline. Once the exit handlers have completed, any other
+// goroutine that is still running after a short settling period is
+// reported as leaked in the ShutdownReport's Leaks field and logged with
+// MsgGoroutinesLeaked. Leaks are not detected if the grace period expires
+// or the shutdown is escalated.
+func (m *Manager) SetLeakDetection(enabled bool) {
+	var baseline map[uint64]bool
+	if enabled {
+		baseline = map[uint64]bool{}
+		for _, g := range goroutines() {
+			baseline[g.id] = true
+		}
+	}
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.leakBaseline = baseline
+}
+
+// goroutine is a running goroutine and its stack trace.
+type goroutine struct {
+	id    uint64
+	stack string
+}
+
+// goroutines returns the running goroutines. The goroutine that invokes
+// goroutines is first.
+func goroutines() []goroutine {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	var a []goroutine
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		s := strings.TrimSpace(string(stack))
+		f := strings.Fields(strings.TrimPrefix(s, "goroutine "))
+		if len(f) == 0 {
+			continue
+		}
+		id, err := strconv.ParseUint(f[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		a = append(a, goroutine{id: id, stack: s})
+	}
+	return a
+}
+
+// leaks returns the stack traces of the goroutines that are running but
+// are not in the baseline, excluding the invoking goroutine and those
+// started by this package.
+func leaks(baseline map[uint64]bool) []string {
+	var stacks []string
+	for i, g := range goroutines() {
+		if i == 0 || baseline[g.id] || ignoredLeak(g.stack) {
+			continue
+		}
+		stacks = append(stacks, g.stack)
+	}
+	return stacks
+}
+
+// ignoredLeak returns true if the stack trace is of a goroutine that is
+// never reported as leaked.
+func ignoredLeak(stack string) bool {
+	for _, f := range ignoredLeaks {
+		if strings.Contains(stack, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectLeaks returns the stack traces of the leaked goroutines if leak
+// detection is enabled. Goroutines that exit before the settling period
+// elapses are not reported.
+func (m *Manager) detectLeaks(ctx context.Context) []string {
+	m.configRWL.RLock()
+	baseline := m.leakBaseline
+	m.configRWL.RUnlock()
+	if baseline == nil {
+		return nil
+	}
+
+	deadline := time.Now().Add(leakSettleTime)
+	delay := time.Millisecond
+	for {
+		stacks := leaks(baseline)
+		if len(stacks) == 0 {
+			return nil
+		}
+		if time.Now().Add(delay).After(deadline) {
+			m.log(ctx, LevelWarn, MsgGoroutinesLeaked, KeyLeaks, len(stacks))
+			return stacks
+		}
+		time.Sleep(delay)
+		if delay *= 2; delay > 100*time.Millisecond {
+			delay = 100 * time.Millisecond
+		}
+	}
+}
+
+// SetLeakDetection sets whether goroutines that are leaked by the program
+// are reported when it exits. Please see the Manager's SetLeakDetection
+// function.
+func SetLeakDetection(enabled bool) {
+	defaultManager.SetLeakDetection(enabled)
+}
+
+// WithLeakDetection returns an Option that sets whether goroutines that
+// are leaked by the program are reported when it exits. Please see the
+// SetLeakDetection function.
+func WithLeakDetection(enabled bool) Option {
+	return func(m *Manager) {
+		m.SetLeakDetection(enabled)
+	}
+}
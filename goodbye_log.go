@@ -0,0 +1,290 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"strconv"
+)
+
+// Level is the severity of a log message.
+type Level int
+
+const (
+	// LevelDebug is used for messages about the execution of individual
+	// exit handlers.
+	LevelDebug Level = iota
+
+	// LevelInfo is used for messages about the lifecycle of the process,
+	// such as the receipt of a signal and the final exit code.
+	LevelInfo
+
+	// LevelWarn is used for messages about exit handlers that timed out.
+	LevelWarn
+
+	// LevelError is used for messages about exit handlers that failed and
+	// the expiration of the grace period.
+	LevelError
+)
+
+// String returns the name of the level, ex. "info".
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return "level(" + strconv.Itoa(int(l)) + ")"
+}
+
+// The messages logged by a Manager. Each message describes a lifecycle
+// event and is logged with the keys listed below.
+const (
+	// MsgSignalReceived is logged with KeySignal and KeyExitCode when a
+	// trapped signal is received.
+	MsgSignalReceived = "signal received"
+
+	// MsgSignalObserved is logged with KeySignal when an observed signal
+	// is received.
+	MsgSignalObserved = "signal observed"
+
+	// MsgSignalMasked is logged with KeySignal when a trapped signal is
+	// received while it is masked and its delivery is deferred.
+	MsgSignalMasked = "signal masked"
+
+	// MsgSignalCoalesced is logged with KeySignal when a signal is
+	// discarded because the same signal was delivered within the coalesce
+	// window.
+	MsgSignalCoalesced = "signal coalesced"
+
+	// MsgStackDumpFailed is logged with KeySignal and KeyError when the
+	// goroutine stack dump cannot be written.
+	MsgStackDumpFailed = "stack dump failed"
+
+	// MsgProfileDumpFailed is logged with KeySignal and KeyError when a
+	// profile cannot be written by the action registered with
+	// RegisterProfileDump.
+	MsgProfileDumpFailed = "profile dump failed"
+
+	// MsgInvalidSignal is logged with KeySignal and KeyError when strict
+	// validation rejects a signal passed to a function that does not
+	// return an error, such as Observe.
+	MsgInvalidSignal = "invalid signal"
+
+	// MsgExitError is logged with KeyError and KeyExitCode when the
+	// ExitErr or Fatal function is invoked, or when a panic is recovered by
+	// RecoverAndExit.
+	MsgExitError = "exit error"
+
+	// MsgConfirmationRequired is logged with KeySignal when an interrupt
+	// signal is received and the shutdown requires confirmation.
+	MsgConfirmationRequired = "confirmation required"
+
+	// MsgShutdownVetoed is logged with KeySignal and KeyError when a
+	// preparer vetoes the shutdown or the shutdown is aborted. The error is
+	// a *VetoError or ErrAborted.
+	MsgShutdownVetoed = "shutdown vetoed"
+
+	// MsgShutdownStarted is logged with KeySignal and KeyExitCode before
+	// the exit handlers are executed.
+	MsgShutdownStarted = "shutdown started"
+
+	// MsgHandlerStarted is logged with KeyHandler and KeyPriority before an
+	// exit handler is executed.
+	MsgHandlerStarted = "handler started"
+
+	// MsgHandlerFinished is logged with KeyHandler, KeyPriority, and
+	// KeyDuration when an exit handler completes without an error.
+	MsgHandlerFinished = "handler finished"
+
+	// MsgHandlerFailed is logged with KeyHandler, KeyPriority, KeyDuration,
+	// and KeyError when an exit handler returns an error.
+	MsgHandlerFailed = "handler failed"
+
+	// MsgHandlerSkipped is logged with KeyHandler and KeyPriority when an
+	// optional exit handler is skipped because the shutdown is running out
+	// of time.
+	MsgHandlerSkipped = "handler skipped"
+
+	// MsgHandlerTimedOut is logged with KeyHandler and KeyPriority when an
+	// exit handler does not complete before its priority level's timeout.
+	MsgHandlerTimedOut = "handler timed out"
+
+	// MsgLevelTimedOut is logged with KeyPriority and KeyDuration when the
+	// handlers of a priority level do not complete before the level's
+	// timeout and the next level is executed.
+	MsgLevelTimedOut = "level timed out"
+
+	// MsgHandlerSlow is logged with KeyHandler, KeyPriority, and
+	// KeyDuration when an exit handler is still running once the slow
+	// handler threshold elapses. The duration is the threshold.
+	MsgHandlerSlow = "handler slow"
+
+	// MsgPreStopDelay is logged with KeyDuration when the Manager begins
+	// waiting for the pre-stop delay.
+	MsgPreStopDelay = "pre-stop delay"
+
+	// MsgShutdownInhibited is logged with KeyInhibitors when the execution
+	// of the exit handlers is delayed by Inhibit.
+	MsgShutdownInhibited = "shutdown inhibited"
+
+	// MsgInhibitTimedOut is logged with KeyInhibitors and KeyDuration when
+	// the maximum inhibit duration expires or the grace period's deadline
+	// passes before the inhibitors are released.
+	MsgInhibitTimedOut = "inhibit timed out"
+
+	// MsgWorkDraining is logged with KeyInFlight when the Manager begins
+	// waiting for in-flight work to complete.
+	MsgWorkDraining = "work draining"
+
+	// MsgWorkDrained is logged with KeyDuration when the in-flight work
+	// completes.
+	MsgWorkDrained = "work drained"
+
+	// MsgWorkDrainTimedOut is logged with KeyInFlight and KeyDuration when
+	// the work timeout expires or the grace period's deadline passes before
+	// the in-flight work completes.
+	MsgWorkDrainTimedOut = "work drain timed out"
+
+	// MsgShutdownEscalated is logged with KeySignal and KeyExitCode when a
+	// trapped signal is received while the exit handlers are running and
+	// the process exits immediately.
+	MsgShutdownEscalated = "shutdown escalated"
+
+	// MsgGracePeriodExpired is logged with KeyGracePeriod and KeyRunning
+	// when the grace period expires.
+	MsgGracePeriodExpired = "grace period expired"
+
+	// MsgShutdownHung is logged with KeyDuration when the hang timeout
+	// expires before the process exits and the process is aborted.
+	MsgShutdownHung = "shutdown hung"
+
+	// MsgReportFailed is logged with KeyError when the shutdown report
+	// cannot be written.
+	MsgReportFailed = "report failed"
+
+	// MsgRestarting is logged when the process is replaced by a new
+	// instance of its binary by the Restart function.
+	MsgRestarting = "restarting"
+
+	// MsgRestartFailed is logged with KeyError when the Restart function
+	// cannot start a new instance of the process's binary.
+	MsgRestartFailed = "restart failed"
+
+	// MsgSuspendFailed is logged with KeySignal and KeyError when the
+	// process cannot be stopped once the suspend observers have been
+	// invoked.
+	MsgSuspendFailed = "suspend failed"
+
+	// MsgGoroutinesLeaked is logged with KeyLeaks when leak detection is
+	// enabled and goroutines are still running once the exit handlers
+	// have completed.
+	MsgGoroutinesLeaked = "goroutines leaked"
+
+	// MsgExiting is logged with KeyExitCode immediately before the process
+	// exits.
+	MsgExiting = "exiting"
+)
+
+// The keys of the keyvals logged by a Manager.
+const (
+	// KeySignal is the key of an os.Signal value.
+	KeySignal = "signal"
+
+	// KeyExitCode is the key of an int exit code.
+	KeyExitCode = "exit_code"
+
+	// KeyHandler is the key of the string name of an exit handler.
+	// Handlers registered without a name are logged as "unnamed".
+	KeyHandler = "handler"
+
+	// KeyPriority is the key of the int priority of an exit handler.
+	KeyPriority = "priority"
+
+	// KeyDuration is the key of the time.Duration an exit handler took to
+	// complete.
+	KeyDuration = "duration"
+
+	// KeyError is the key of an error returned by an exit handler.
+	KeyError = "error"
+
+	// KeyGracePeriod is the key of the time.Duration grace period.
+	KeyGracePeriod = "grace_period"
+
+	// KeyRunning is the key of the []string names of the exit handlers
+	// that are running.
+	KeyRunning = "running"
+
+	// KeyLeaks is the key of the int number of leaked goroutines.
+	KeyLeaks = "leaks"
+
+	// KeyInhibitors is the key of the []string reasons provided to Inhibit
+	// that have not been released.
+	KeyInhibitors = "inhibitors"
+
+	// KeyInFlight is the key of the int amount of in-flight work.
+	KeyInFlight = "in_flight"
+)
+
+// Logger is the interface used to log the lifecycle events of a Manager.
+// The keyvals are alternating keys and values, ex. "handler", "db-pool",
+// "priority", 1000. The keys are always strings.
+//
+// Adapters for log/slog, zap, and logrus are provided by the slogadapter,
+// zapadapter, and logrusadapter packages.
+type Logger interface {
+	Log(ctx context.Context, level Level, msg string, keyvals ...interface{})
+}
+
+// LoggerFunc is a function that implements the Logger interface.
+type LoggerFunc func(ctx context.Context, level Level, msg string, keyvals ...interface{})
+
+// Log invokes the function.
+func (f LoggerFunc) Log(ctx context.Context, level Level, msg string, keyvals ...interface{}) {
+	f(ctx, level, msg, keyvals...)
+}
+
+// SetLogger sets the Logger used to log the lifecycle events of the
+// Manager. A nil value, the default, means the events are not logged.
+func (m *Manager) SetLogger(l Logger) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.logger = l
+}
+
+// log logs a message with the Manager's Logger, if it has one.
+func (m *Manager) log(ctx context.Context, level Level, msg string, keyvals ...interface{}) {
+	m.configRWL.RLock()
+	l := m.logger
+	m.configRWL.RUnlock()
+	if l != nil {
+		l.Log(ctx, level, msg, keyvals...)
+	}
+}
+
+// hasLogger returns true if the Manager has a Logger.
+func (m *Manager) hasLogger() bool {
+	m.configRWL.RLock()
+	defer m.configRWL.RUnlock()
+	return m.logger != nil
+}
+
+// WithLogger returns an Option that sets the Logger used to log the
+// lifecycle events. Please see the SetLogger function.
+func WithLogger(l Logger) Option {
+	return func(m *Manager) {
+		m.SetLogger(l)
+	}
+}
+
+// SetLogger sets the Logger used to log the lifecycle events of the
+// package-level functions. A nil value, the default, means the events are
+// not logged.
+func SetLogger(l Logger) {
+	defaultManager.SetLogger(l)
+}
@@ -0,0 +1,909 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// Manager is an independent set of exit handlers and trapped signals.
+//
+// The package-level functions operate on a default Manager. Libraries and
+// test cases that need to manage their own handlers without affecting the
+// package-level state may create a Manager with the New function.
+type Manager struct {
+	// ExitCode is the exit code used by the Exit function if it is called
+	// with an exit code value of -1.
+	ExitCode int
+
+	// handlers is a list of exit handlers to invoke when the process exits
+	// or receives a signal that causes an exit behavior
+	handlers    map[int][]*handler
+	handlersRWL sync.RWMutex
+
+	// seq is the number of handlers that have been registered. It is
+	// used to record the order in which handlers were registered.
+	seq uint64
+
+	// notified is the list of signals that are trapped as a result of the
+	// Notify function. It is used to determine whether signals have been
+	// removed when the trapped signals change.
+	notified []os.Signal
+
+	// sigc is the channel on which the signals trapped as a result of the
+	// Notify function are received. It is created, and the dispatcher that
+	// reads from it is started, the first time signals are trapped. The
+	// Reset function stops and closes the channel, which stops the
+	// dispatcher.
+	sigc chan os.Signal
+
+	// cycle is the state of the current shutdown cycle. It is replaced
+	// by the Reset function.
+	cycle    *cycle
+	cycleRWL sync.RWMutex
+
+	// lock is used to prevent the Notify and Reset functions from being
+	// called concurrently. It is not held while the exit handlers are
+	// executed, so a Manager whose handlers are still running once the
+	// Shutdown function returns may be reset.
+	lock sync.Mutex
+
+	// errorExitCode is the exit code used when one or more exit handlers
+	// return an error and the process would otherwise exit with zero.
+	errorExitCode int
+
+	// onError is invoked with the errors returned by the exit handlers.
+	onError func(ctx context.Context, err error)
+
+	// onComplete is invoked with the ShutdownReport before the process
+	// exits.
+	onComplete func(ShutdownReport)
+
+	// reportWriter is the destination of the ShutdownReport.
+	reportWriter *reportWriter
+
+	// stackDump is the destination of the goroutine stack dump written
+	// when SIGQUIT is received.
+	stackDump *stackDump
+
+	// gracePeriodStackDump is the destination of the goroutine stack dump
+	// written when the grace period expires.
+	gracePeriodStackDump *stackDump
+
+	// panicPolicy describes how panicking exit handlers are handled.
+	panicPolicy PanicPolicy
+
+	// panicExitCode is the exit code used when a panic is converted into
+	// a shutdown.
+	panicExitCode int
+
+	// hooks are invoked around the execution of the exit handlers.
+	hooks hooks
+
+	// exitCoderPolicy selects the exit code from handler errors that
+	// implement ExitCoder.
+	exitCoderPolicy ExitCoderPolicy
+
+	// exitCodePolicy computes the exit code once the exit handlers have
+	// completed. A nil value means the default behavior is used.
+	exitCodePolicy ExitCodePolicy
+
+	// gracePeriod is the amount of time the exit handlers are given to
+	// complete before the process is forcibly exited with forcedExitCode.
+	gracePeriod    time.Duration
+	forcedExitCode int
+
+	// leakBaseline is the set of IDs of the goroutines that were running
+	// when leak detection was enabled. It is nil if leak detection is
+	// disabled.
+	leakBaseline map[uint64]bool
+
+	// hangTimeout is the amount of time after which a shutdown that has
+	// not exited is aborted.
+	hangTimeout time.Duration
+
+	// useEnv indicates whether the Notify functions read the environment
+	// variables that configure the Manager.
+	useEnv bool
+
+	// logger is used to log the lifecycle events of the Manager.
+	logger Logger
+
+	// exiter is the function used to exit the process.
+	exiter func(code int)
+
+	// exitOwner is invoked instead of the exiter in the cooperative mode.
+	exitOwner func(code int)
+
+	// concurrency is the maximum number of exit handlers that share a
+	// priority level that may be executed concurrently.
+	concurrency int
+
+	// optionalThreshold is the amount of time that must remain before the
+	// exit handlers' deadline for the optional handlers to be executed.
+	optionalThreshold time.Duration
+
+	// slowThreshold is the amount of time after which a running exit
+	// handler is logged as slow.
+	slowThreshold time.Duration
+
+	// timeouts is the amount of time the handlers of a priority level
+	// are given to complete before the next level is executed.
+	timeouts map[int]time.Duration
+
+	// escalation is true if a trapped signal received while the exit
+	// handlers are running exits the process with escalationExitCode.
+	escalation         bool
+	escalationExitCode int
+
+	// preStopDelay is the amount of time to wait before executing the exit
+	// handlers.
+	preStopDelay time.Duration
+
+	// ignored is the set of signals ignored with the Ignore function. The
+	// value is true if the signal was already ignored, in which case Reset
+	// does not restore its disposition.
+	ignored map[os.Signal]bool
+
+	// relays are the channels to which trapped signals are relayed.
+	relays relays
+
+	// masks are the signals whose delivery is deferred by the Mask
+	// function.
+	masks masks
+
+	// coalescer discards repeated deliveries of the same signal within
+	// the coalesce window.
+	coalescer coalescer
+
+	// strict is true if signals that cannot be trapped or are not available
+	// on the operating system are rejected.
+	strict bool
+
+	// inhibitors delay the execution of the exit handlers for at most
+	// maxInhibit.
+	inhibitors inhibitors
+	maxInhibit time.Duration
+
+	// work is the in-flight work that is drained for at most workTimeout
+	// during a shutdown.
+	work        work
+	workTimeout time.Duration
+
+	// feedback is invoked with user feedback.
+	feedback FeedbackFunc
+
+	// confirmation is the state of the interactive confirmation of
+	// interrupt signals.
+	confirmation confirmation
+
+	// shellExitCodes is true if trapped signals cause the process to exit
+	// with 128 plus the signal's number.
+	shellExitCodes bool
+
+	// specs is the list of signals trapped by the Notify functions when
+	// they are invoked without any signals. If empty then the default
+	// signals for the operating system are trapped.
+	specs []SignalSpec
+
+	// running is the set of exit handlers that are currently executing.
+	// It is used to report which handlers did not complete before the
+	// grace period expired.
+	running     map[*handler]struct{}
+	runningLock sync.Mutex
+
+	// ctx is the context provided to the Notify function. It is provided
+	// to the signal observers.
+	ctx context.Context
+
+	// trapped is the map of the signals trapped by the Manager and their
+	// exit codes. The Notify functions add to it and UnNotify removes
+	// from it.
+	trapped map[os.Signal]int
+
+	// observers are the functions invoked when observed signals are
+	// received.
+	observers observers
+
+	// preparers are the functions that may veto a shutdown caused by a
+	// trapped signal.
+	preparers preparers
+
+	// lastSignal is the most recent trapped or observed signal that was
+	// received.
+	lastSignal lastSignal
+
+	// configRWL guards the configuration fields that may be set with
+	// the Manager's Set* and On* functions.
+	configRWL sync.RWMutex
+}
+
+// cycle is the state of a single shutdown cycle.
+type cycle struct {
+	// once is used by the handleOnce function to execute the exit handlers
+	// and os.Exit exactly once, regardless of how many times Exit is invoked
+	// or if a signal is received at the same time that Exit is invoked
+	once sync.Once
+
+	// done is closed when the process begins to exit, before the exit
+	// handlers are executed.
+	done chan struct{}
+
+	// exitSig and exitCode are the signal and exit code with which the
+	// process is exiting. They are set before done is closed.
+	exitSig  os.Signal
+	exitCode int
+
+	// escalate exits the process immediately. It is set while the exit
+	// handlers are running if escalation is enabled.
+	escalate escalation
+
+	// ownerOnce is used to invoke the exit owner at most once.
+	ownerOnce sync.Once
+}
+
+func newCycle() *cycle {
+	return &cycle{done: make(chan struct{})}
+}
+
+// handler is a registered exit handler.
+type handler struct {
+	f        ExitFunc
+	name     string
+	priority int
+	seq      uint64
+
+	// after is a list of the names of the handlers that must complete
+	// before this handler is executed.
+	after []string
+
+	// when reports whether the handler is executed for a signal. A nil
+	// value means the handler is always executed.
+	when func(s os.Signal) bool
+
+	// optional is true if the handler is skipped when the shutdown is
+	// running out of time.
+	optional bool
+}
+
+// applies returns true if the handler is executed for the signal.
+func (h *handler) applies(s os.Signal) bool {
+	return h.when == nil || h.when(s)
+}
+
+// String returns the handler's name or "unnamed" if the handler does not
+// have a name.
+func (h *handler) String() string {
+	if h.name == "" {
+		return "unnamed"
+	}
+	return h.name
+}
+
+// New returns a new Manager.
+func New() *Manager {
+	return &Manager{
+		handlers:           map[int][]*handler{},
+		running:            map[*handler]struct{}{},
+		timeouts:           map[int]time.Duration{PhaseCritical.Priority: DefaultCriticalTimeout},
+		cycle:              newCycle(),
+		errorExitCode:      1,
+		forcedExitCode:     DefaultForcedExitCode,
+		escalationExitCode: DefaultEscalationExitCode,
+		panicExitCode:      DefaultPanicExitCode,
+		exiter:             os.Exit,
+
+		gracePeriodStackDump: &stackDump{w: os.Stderr},
+	}
+}
+
+// Register registers a function to be invoked when this process exits
+// normally or due to a process signal.
+//
+// Handlers registered with this function are given a priority of 0.
+//
+// The returned function removes the handler when invoked.
+func (m *Manager) Register(f ExitHandler) func() {
+	return m.RegisterWithPriority(f, 0)
+}
+
+// RegisterWithPriority registers a function to be invoked when
+// this process exits normally or due to a process signal.
+//
+// The priority determines when an exit handler is executed. Handlers
+// with a lower integer value execute first and higher integer values
+// execute later. If multiple handlers share the same priority level
+// then the handlers are invoked in the order in which they were
+// registered.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterWithPriority(f ExitHandler, priority int) func() {
+	return m.RegisterFuncWithPriority(func(ctx context.Context, s os.Signal) error {
+		f(ctx, s)
+		return nil
+	}, priority)
+}
+
+// RegisterFunc registers a function that returns an error to be invoked
+// when this process exits normally or due to a process signal.
+//
+// Handlers registered with this function are given a priority of 0.
+//
+// The returned function removes the handler when invoked.
+func (m *Manager) RegisterFunc(f ExitFunc) func() {
+	return m.RegisterFuncWithPriority(f, 0)
+}
+
+// RegisterFuncWithPriority registers a function that returns an error to
+// be invoked when this process exits normally or due to a process signal.
+// Please see RegisterWithPriority for a description of the priority.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterFuncWithPriority(f ExitFunc, priority int) func() {
+	return m.register(&handler{f: f, priority: priority})
+}
+
+// RegisterNamed registers a named function to be invoked when this process
+// exits normally or due to a process signal. Please see RegisterWithPriority
+// for a description of the priority.
+//
+// The name is used to identify the handler in errors and diagnostic output.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterNamed(name string, f ExitHandler, priority int) func() {
+	return m.RegisterNamedFunc(name, func(ctx context.Context, s os.Signal) error {
+		f(ctx, s)
+		return nil
+	}, priority)
+}
+
+// RegisterNamedFunc registers a named function that returns an error to be
+// invoked when this process exits normally or due to a process signal.
+// Please see RegisterWithPriority for a description of the priority.
+//
+// The name is used to identify the handler in errors and diagnostic output.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterNamedFunc(name string, f ExitFunc, priority int) func() {
+	return m.register(&handler{f: f, name: name, priority: priority})
+}
+
+func (m *Manager) register(h *handler) func() {
+	m.handlersRWL.Lock()
+	defer m.handlersRWL.Unlock()
+	m.add(h)
+	return func() { m.unregister(h) }
+}
+
+// add adds a handler to the handler map. The caller must hold the
+// handlersRWL write lock.
+func (m *Manager) add(h *handler) {
+	m.seq++
+	h.seq = m.seq
+	priority := h.priority
+	if a, ok := m.handlers[priority]; !ok {
+		m.handlers[priority] = []*handler{h}
+	} else {
+		m.handlers[priority] = append(a, h)
+	}
+}
+
+func (m *Manager) unregister(h *handler) {
+	m.handlersRWL.Lock()
+	defer m.handlersRWL.Unlock()
+	a := m.handlers[h.priority]
+	for i := range a {
+		if a[i] != h {
+			continue
+		}
+		a = append(a[:i:i], a[i+1:]...)
+		if len(a) == 0 {
+			delete(m.handlers, h.priority)
+		} else {
+			m.handlers[h.priority] = a
+		}
+		return
+	}
+}
+
+// SetErrorExitCode sets the exit code used when one or more exit handlers
+// return an error and the process would otherwise exit with an exit code
+// of zero. The default value is 1. A value of 0 means handler errors do
+// not affect the exit code.
+func (m *Manager) SetErrorExitCode(exitCode int) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.errorExitCode = exitCode
+}
+
+// OnError sets a function that is invoked after all of the exit handlers
+// have been executed if one or more of them returned an error. The error
+// provided to the function is of type Errors.
+func (m *Manager) OnError(f func(ctx context.Context, err error)) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.onError = f
+}
+
+// SetGracePeriod sets the amount of time the exit handlers are given to
+// complete. If the handlers are still running when the grace period
+// expires then the process is forcibly exited with the exit code set with
+// SetForcedExitCode. A value of zero, the default, means there is no
+// grace period and the handlers may run indefinitely.
+//
+// The context provided to the exit handlers has a deadline at the end of
+// the grace period, so handlers may pass it to functions such as
+// http.Server's Shutdown to bound their work by the time that remains.
+func (m *Manager) SetGracePeriod(d time.Duration) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.gracePeriod = d
+}
+
+// SetSlowHandlerThreshold sets the amount of time after which an exit
+// handler that is still running is logged with MsgHandlerSlow. The handler
+// is not interrupted, so the threshold surfaces chronically slow cleanup
+// independently of any timeout. A value of zero, the default, disables the
+// warning.
+func (m *Manager) SetSlowHandlerThreshold(d time.Duration) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.slowThreshold = d
+}
+
+// SetForcedExitCode sets the exit code used when the process is forcibly
+// exited because the grace period expired. The default value is
+// DefaultForcedExitCode.
+func (m *Manager) SetForcedExitCode(exitCode int) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.forcedExitCode = exitCode
+}
+
+// SetConcurrency sets the maximum number of exit handlers that share the
+// same priority level that may be executed concurrently. Priority levels
+// are always executed sequentially.
+//
+// A value of 0 or 1, the default, executes handlers one at a time in the
+// order in which they were registered. A negative value means there is no
+// limit.
+func (m *Manager) SetConcurrency(n int) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.concurrency = n
+}
+
+// context returns the context provided to the Notify function or
+// context.Background if Notify has not been invoked.
+func (m *Manager) context() context.Context {
+	m.configRWL.RLock()
+	defer m.configRWL.RUnlock()
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// SetExiter sets the function used to exit the process after the exit
+// handlers have completed or the grace period has expired. The default
+// value is os.Exit. Tests may use this function to verify the exit code
+// without terminating the test binary. A nil value restores the default.
+func (m *Manager) SetExiter(f func(code int)) {
+	if f == nil {
+		f = os.Exit
+	}
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.exiter = f
+}
+
+// Exit executes all of the registered exit handlers. Please see the
+// package-level Exit function for a description of the arguments.
+func (m *Manager) Exit(ctx context.Context, exitCode int, opts ...Option) {
+	m.apply(opts)
+	if exitCode < 0 {
+		m.configRWL.RLock()
+		exitCode = m.ExitCode
+		m.configRWL.RUnlock()
+	}
+	m.handleOnce(ctx, noSigVal, exitCode)
+}
+
+// Notify begins trapping the specified signals. Please see the package-level
+// Notify function for a description of the signals argument.
+func (m *Manager) Notify(ctx context.Context, signals ...interface{}) error {
+	var (
+		s     os.Signal
+		specs []SignalSpec
+		opts  []Option
+	)
+	for _, v := range signals {
+		switch tv := v.(type) {
+		case Option:
+			opts = append(opts, tv)
+		case os.Signal:
+			s = tv
+			specs = append(specs, SignalSpec{Signal: s})
+		case string:
+			ps, err := ParseSignal(tv)
+			if err != nil {
+				return err
+			}
+			s = ps
+			specs = append(specs, SignalSpec{Signal: s})
+		case int:
+			if s == nil {
+				return &SignalError{Err: ErrMissingSignal}
+			}
+			specs[len(specs)-1].ExitCode = tv
+			s = nil
+		default:
+			return &SignalError{
+				Signal: unknownSignal(fmt.Sprintf("%v (%T)", v, v)),
+				Err:    ErrInvalidSignal,
+			}
+		}
+	}
+	m.apply(opts)
+	return m.NotifySignals(ctx, specs...)
+}
+
+// NotifySignals begins trapping the specified signals. Please see the
+// package-level NotifySignals function for a description of the specs
+// argument.
+func (m *Manager) NotifySignals(ctx context.Context, specs ...SignalSpec) error {
+	specs, err := m.applyEnv(specs)
+	if err != nil {
+		return err
+	}
+
+	if len(specs) == 0 {
+		m.configRWL.RLock()
+		specs = m.specs
+		m.configRWL.RUnlock()
+	}
+
+	// The map is always a copy so that SetSignalExitCode may update it.
+	sigs := map[os.Signal]int{}
+	if len(specs) == 0 {
+		for s, x := range defaultSignals {
+			sigs[s] = x
+		}
+	} else {
+		for _, spec := range specs {
+			if err := m.validateSignal(spec.Signal); err != nil {
+				return err
+			}
+			if _, ok := sigs[spec.Signal]; ok {
+				return &SignalError{Signal: spec.Signal, Err: ErrDuplicateSignal}
+			}
+			sigs[spec.Signal] = spec.ExitCode
+		}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// The signals are merged with the signals trapped by previous calls.
+	// The exit code of a signal that is already trapped is replaced.
+	m.configRWL.RLock()
+	for s, x := range m.trapped {
+		if _, ok := sigs[s]; !ok {
+			sigs[s] = x
+		}
+	}
+	m.configRWL.RUnlock()
+
+	m.trap(ctx, sigs)
+	return nil
+}
+
+// UnNotify stops trapping the specified signals. The other trapped signals
+// remain trapped. If no signals are specified then all of the signals
+// trapped as a result of the Notify functions are no longer trapped.
+// Signals that are not trapped are ignored.
+func (m *Manager) UnNotify(signals ...os.Signal) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.configRWL.RLock()
+	ctx := m.ctx
+	sigs := map[os.Signal]int{}
+	if len(signals) > 0 {
+		for s, x := range m.trapped {
+			sigs[s] = x
+		}
+		for _, s := range signals {
+			delete(sigs, s)
+		}
+	}
+	m.configRWL.RUnlock()
+
+	m.trap(ctx, sigs)
+}
+
+// trap replaces the trapped signals with sigs. The caller must hold the
+// Manager's lock.
+func (m *Manager) trap(ctx context.Context, sigs map[os.Signal]int) {
+	var removed bool
+	for _, s := range m.notified {
+		if _, ok := sigs[s]; !ok {
+			removed = true
+			break
+		}
+	}
+	notified := make([]os.Signal, 0, len(sigs))
+	for s := range sigs {
+		notified = append(notified, s)
+	}
+
+	m.configRWL.Lock()
+	m.ctx, m.trapped = ctx, sigs
+	for _, s := range notified {
+		delete(m.ignored, s)
+	}
+	m.configRWL.Unlock()
+	m.notified = notified
+
+	switch {
+	case len(notified) == 0:
+		if m.sigc != nil {
+			signal.Stop(m.sigc)
+			close(m.sigc)
+			m.sigc = nil
+		}
+		return
+	case m.sigc == nil:
+		m.sigc = make(chan os.Signal, 1)
+		go m.dispatcher(m.sigc)
+	case removed:
+		// The os/signal package cannot stop relaying a single signal to a
+		// channel, so the channel is stopped and then notified of the
+		// remaining trapped signals.
+		signal.Stop(m.sigc)
+	}
+	signal.Notify(m.sigc, notified...)
+	m.notifyEvents(sigs)
+}
+
+// dispatcher dispatches the signals received on the channel until it is
+// closed. Each signal is dispatched from its own goroutine so that a
+// signal received while the exit handlers are running may escalate the
+// shutdown.
+func (m *Manager) dispatcher(sigc chan os.Signal) {
+	for s := range sigc {
+		m.configRWL.RLock()
+		sigs := m.trapped
+		m.configRWL.RUnlock()
+		if m.coalesce(s, false) || m.mask(s, sigs) {
+			continue
+		}
+		go m.dispatch(m.context(), s, sigs)
+	}
+}
+
+// dispatch executes the exit handlers and exits the program if the
+// signal is one of the trapped signals.
+func (m *Manager) dispatch(ctx context.Context, s os.Signal, sigs map[os.Signal]int) {
+
+	// Get the exit code associated with the signal. If no
+	// exit code exists then the signal was not trapped and
+	// should not be handled.
+	m.configRWL.RLock()
+	x, ok := sigs[s]
+	m.configRWL.RUnlock()
+	if !ok {
+		return
+	}
+	x = m.signalExitCode(s, x)
+	m.lastSignal.set(s)
+	m.relay(s)
+
+	m.log(ctx, LevelInfo, MsgSignalReceived, KeySignal, s, KeyExitCode, x)
+	m.dumpStacks(ctx, s)
+
+	// Exit immediately if the exit handlers are already running and
+	// escalation is enabled.
+	if f := m.currentCycle().escalate.get(); f != nil {
+		f(s)
+		return
+	}
+
+	// Do not begin exiting until an interrupt signal is confirmed.
+	if !m.confirmation.confirmed(ctx, s) {
+		m.log(ctx, LevelInfo, MsgConfirmationRequired, KeySignal, s)
+		m.feedbackf(ctx, Feedback{Event: FeedbackConfirm, Signal: s})
+		return
+	}
+
+	// Do not begin exiting if a preparer vetoes the shutdown.
+	if err := m.prepare(ctx, s); err != nil {
+		m.log(ctx, LevelWarn, MsgShutdownVetoed, KeySignal, s, KeyError, err)
+		m.feedbackf(ctx, Feedback{Event: FeedbackVetoed, Signal: s, Err: err})
+		return
+	}
+
+	// Execute the signal handlers and exit the program.
+	m.handleOnce(ctx, s, x)
+}
+
+// Reset clears the list of registered exit handlers and observers and
+// stops trapping the signals that were trapped as a result of the Notify
+// and Observe functions.
+//
+// Reset also re-arms the Manager so that a subsequent trapped signal or
+// invocation of the Exit function executes the exit handlers registered
+// after Reset. The configuration set with the Manager's Set* and On*
+// functions is retained. Reset should not be invoked while the exit
+// handlers are being executed, unless they were executed by the Shutdown
+// function and it has returned.
+func (m *Manager) Reset() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.trap(nil, nil)
+	m.resetObservers()
+	m.resetIgnored()
+	m.resetMasked()
+	m.resetPreparers()
+
+	m.handlersRWL.Lock()
+	m.handlers = map[int][]*handler{}
+	m.handlersRWL.Unlock()
+
+	m.cycleRWL.Lock()
+	m.cycle = newCycle()
+	m.cycleRWL.Unlock()
+}
+
+// currentCycle returns the state of the current shutdown cycle.
+func (m *Manager) currentCycle() *cycle {
+	m.cycleRWL.RLock()
+	defer m.cycleRWL.RUnlock()
+	return m.cycle
+}
+
+func (m *Manager) handleOnce(ctx context.Context, s os.Signal, x int) {
+	c := m.currentCycle()
+	c.once.Do(func() {
+		c.exitSig, c.exitCode = s, x
+		close(c.done)
+
+		m.log(ctx, LevelInfo, MsgShutdownStarted, KeySignal, s, KeyExitCode, x)
+
+		m.configRWL.RLock()
+		gracePeriod, forcedExitCode := m.gracePeriod, m.forcedExitCode
+		sd := m.gracePeriodStackDump
+		exit, onComplete, rw := m.exiter, m.onComplete, m.reportWriter
+		policy, hk := m.panicPolicy, m.hooks.copy()
+		escalate, escalationExitCode := m.escalation, m.escalationExitCode
+		if m.exitOwner != nil {
+			exit = c.ownedExiter(m.exitOwner)
+		}
+		m.configRWL.RUnlock()
+		if IsRestart(ctx) {
+			exit = m.restartExiter(ctx, exit)
+		}
+		// The process is neither aborted nor sent a stack dump when the
+		// Shutdown function executes the handlers, since it is not the
+		// Manager's process to end.
+		if r := shutdownResultFrom(ctx); r != nil {
+			exit, onComplete = r.exit, r.onComplete(onComplete)
+			sd = nil
+		} else {
+			defer m.watchdog(ctx)()
+		}
+
+		m.feedbackf(ctx, Feedback{
+			Event: FeedbackShutdown, Signal: s, ExitCode: x, Escalation: escalate,
+		})
+
+		// The report is delivered at most once, either when the handlers
+		// complete or when the grace period expires.
+		rec := newRecorder(policy)
+		rec.hooks, rec.cause = hk, ExitError(ctx)
+		var reportOnce sync.Once
+		complete := func(x int, err error, forced bool) {
+			if onComplete == nil && rw == nil {
+				return
+			}
+			reportOnce.Do(func() {
+				r := rec.report(s, x, err, forced)
+				if rw != nil {
+					m.writeReport(ctx, rw, r)
+				}
+				if onComplete != nil {
+					onComplete(r)
+				}
+			})
+		}
+
+		// Exit immediately if another trapped signal is received while the
+		// handlers are running.
+		if escalate {
+			c.escalate.set(func(sig os.Signal) {
+				m.log(ctx, LevelWarn, MsgShutdownEscalated,
+					KeySignal, sig, KeyExitCode, escalationExitCode)
+				m.feedbackf(ctx, Feedback{
+					Event: FeedbackEscalated, Signal: sig,
+					ExitCode: escalationExitCode, Escalation: true,
+				})
+				complete(escalationExitCode, nil, true)
+				m.log(ctx, LevelInfo, MsgExiting, KeyExitCode, escalationExitCode)
+				exit(escalationExitCode)
+			})
+			defer c.escalate.set(nil)
+		}
+
+		// Force the process to exit if the handlers do not complete
+		// before the grace period expires.
+		if gracePeriod > 0 {
+			t := time.AfterFunc(gracePeriod, func() {
+				m.reportRunning(ctx, s, gracePeriod, sd)
+				complete(forcedExitCode, nil, true)
+				m.log(ctx, LevelInfo, MsgExiting, KeyExitCode, forcedExitCode)
+				exit(forcedExitCode)
+			})
+			defer t.Stop()
+		}
+
+		// The context provided to the handlers expires with the grace
+		// period.
+		hctx := ctx
+		if gracePeriod > 0 {
+			var cancel context.CancelFunc
+			hctx, cancel = context.WithDeadline(ctx, rec.start.Add(gracePeriod))
+			defer cancel()
+		}
+
+		// The critical tier is executed before anything that may delay
+		// the shutdown.
+		errs := m.handle(hctx, s, rec, true)
+		m.preStop(hctx)
+		m.waitInhibitors(hctx)
+		for _, f := range hk.beforeAll {
+			f(ctx, s)
+		}
+		errs = append(errs, m.handle(hctx, s, rec, false)...)
+		err := errs.err()
+		m.configRWL.RLock()
+		exitCodePolicy := m.exitCodePolicy
+		m.configRWL.RUnlock()
+		if err != nil {
+			m.configRWL.RLock()
+			onError, errorExitCode := m.onError, m.errorExitCode
+			exitCoderPolicy := m.exitCoderPolicy
+			m.configRWL.RUnlock()
+			if onError != nil {
+				onError(ctx, err)
+			}
+			if x == 0 && exitCodePolicy == nil {
+				x = errorExitCode
+				if code, ok := exitCoderCode(err, exitCoderPolicy); ok {
+					x = code
+				}
+			}
+		}
+		if exitCodePolicy != nil {
+			x = exitCodePolicy.ExitCode(s, err, x)
+		}
+		rec.setLeaks(m.detectLeaks(ctx))
+		if len(hk.afterAll) > 0 {
+			r := rec.report(s, x, err, false)
+			for _, f := range hk.afterAll {
+				f(ctx, r)
+			}
+		}
+		complete(x, err, false)
+		m.log(ctx, LevelInfo, MsgExiting, KeyExitCode, x)
+		exit(x)
+	})
+}
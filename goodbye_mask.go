@@ -0,0 +1,147 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"os"
+	"sync"
+)
+
+// masks is the set of signals whose delivery is deferred by the Mask
+// function and the signals that were received while they were masked.
+type masks struct {
+	// all is the number of masks that apply to all trapped signals.
+	all int
+
+	// bySig is the number of masks that apply to each signal.
+	bySig map[os.Signal]int
+
+	// queued is the list of masked signals in the order they were
+	// received. A signal received more than once is queued once.
+	queued []maskedSignal
+
+	sync.Mutex
+}
+
+// maskedSignal is a trapped signal received while it was masked along with
+// the trapped signals at the time it was received.
+type maskedSignal struct {
+	s    os.Signal
+	sigs map[os.Signal]int
+}
+
+// masked returns true if the signal is masked. The caller must hold the
+// lock.
+func (k *masks) masked(s os.Signal) bool {
+	return k.all > 0 || k.bySig[s] > 0
+}
+
+// Mask defers the delivery of the specified trapped signals, or all of the
+// trapped signals if none are specified, until the returned function is
+// invoked. This allows an operation that must not be interrupted part way
+// through, such as writing a file and renaming it, to complete before a
+// signal begins the shutdown:
+//
+//	unmask := goodbye.Mask(syscall.SIGTERM, syscall.SIGINT)
+//	defer unmask()
+//
+// Signals received while masked are queued rather than dropped, and are
+// delivered in the order they were received once no mask applies to them.
+// A signal received more than once while masked is delivered once. Masks
+// may be nested, and they do not defer signals received by observers or
+// the Exit functions.
+//
+// The returned function unmasks the signals when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) Mask(signals ...os.Signal) func() {
+	signals = append([]os.Signal{}, signals...)
+
+	m.masks.Lock()
+	if len(signals) == 0 {
+		m.masks.all++
+	}
+	for _, s := range signals {
+		if m.masks.bySig == nil {
+			m.masks.bySig = map[os.Signal]int{}
+		}
+		m.masks.bySig[s]++
+	}
+	m.masks.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { m.unmask(signals) })
+	}
+}
+
+// unmask removes a mask created by the Mask function and delivers the
+// queued signals to which no mask applies.
+func (m *Manager) unmask(signals []os.Signal) {
+	m.masks.Lock()
+	if len(signals) == 0 {
+		m.masks.all--
+	}
+	for _, s := range signals {
+		if m.masks.bySig[s]--; m.masks.bySig[s] <= 0 {
+			delete(m.masks.bySig, s)
+		}
+	}
+	var ready []maskedSignal
+	queued := m.masks.queued[:0]
+	for _, q := range m.masks.queued {
+		if m.masks.masked(q.s) {
+			queued = append(queued, q)
+		} else {
+			ready = append(ready, q)
+		}
+	}
+	m.masks.queued = queued
+	m.masks.Unlock()
+
+	for _, q := range ready {
+		go m.dispatch(m.context(), q.s, q.sigs)
+	}
+}
+
+// mask queues the signal if it is trapped and masked. The returned boolean
+// is true if the signal was queued and should not be dispatched.
+func (m *Manager) mask(s os.Signal, sigs map[os.Signal]int) bool {
+	m.configRWL.RLock()
+	_, ok := sigs[s]
+	m.configRWL.RUnlock()
+	if !ok {
+		return false
+	}
+
+	m.masks.Lock()
+	if !m.masks.masked(s) {
+		m.masks.Unlock()
+		return false
+	}
+	for _, q := range m.masks.queued {
+		if q.s == s {
+			m.masks.Unlock()
+			return true
+		}
+	}
+	m.masks.queued = append(m.masks.queued, maskedSignal{s: s, sigs: sigs})
+	m.masks.Unlock()
+
+	m.log(m.context(), LevelInfo, MsgSignalMasked, KeySignal, s)
+	return true
+}
+
+// resetMasked discards the queued signals. The masks created by the Mask
+// function are retained.
+func (m *Manager) resetMasked() {
+	m.masks.Lock()
+	defer m.masks.Unlock()
+	m.masks.queued = nil
+}
+
+// Mask defers the delivery of the specified trapped signals, or all of the
+// trapped signals if none are specified, until the returned function is
+// invoked. Please see the Manager's Mask function.
+func Mask(signals ...os.Signal) func() {
+	return defaultManager.Mask(signals...)
+}
@@ -0,0 +1,210 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+)
+
+// ObserverFunc is a function that is registered with the "Observe" function
+// and is invoked when this process receives a signal. Unlike exit handlers,
+// observers do not cause the process to exit.
+type ObserverFunc func(ctx context.Context, s os.Signal)
+
+// observer is a registered signal observer.
+type observer struct {
+	f        ObserverFunc
+	sig      os.Signal
+	name     string
+	priority int
+
+	// suspend is true if the observer was registered with the OnSuspend
+	// function, in which case the process is stopped once the observers
+	// of the signal have been invoked.
+	suspend bool
+}
+
+// observers is the state of the signals that are observed rather than
+// trapped for the purpose of exiting the process.
+type observers struct {
+	// byS is the list of observers for each observed signal in the order
+	// in which the observers were registered.
+	byS map[os.Signal][]*observer
+
+	// sigc is the channel on which the observed signals are received.
+	// It is created, and the goroutine that reads from it is started,
+	// the first time a signal is observed.
+	sigc chan os.Signal
+
+	sync.RWMutex
+}
+
+// Observe registers a function to be invoked each time this process
+// receives the specified signal. Observing a signal does not cause the
+// process to exit when the signal is received, nor does it execute the
+// exit handlers. This is useful for signals such as SIGHUP that are used
+// to reload configuration or rotate logs.
+//
+// Observers are invoked in the order of their priority, and observers
+// that share a priority are invoked in the order in which they were
+// registered. The context provided to an observer is the one provided to
+// the Notify function or context.Background if Notify has not been
+// invoked.
+//
+// The returned function removes the observer when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) Observe(sig os.Signal, f ObserverFunc) func() {
+	return m.addObserver(&observer{f: f, sig: sig})
+}
+
+// ObserveWithPriority registers a function to be invoked each time this
+// process receives the specified signal. Please see RegisterWithPriority
+// for a description of the priority. Observers registered with the
+// Observe function are given a priority of 0.
+//
+// The returned function removes the observer when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) ObserveWithPriority(
+	sig os.Signal, f ObserverFunc, priority int) func() {
+	return m.addObserver(&observer{f: f, sig: sig, priority: priority})
+}
+
+func (m *Manager) addObserver(o *observer) func() {
+	sig := o.sig
+	if !m.checkSignal(sig) {
+		return func() {}
+	}
+
+	m.observers.Lock()
+	defer m.observers.Unlock()
+
+	if m.observers.sigc == nil {
+		m.observers.sigc = make(chan os.Signal, 1)
+		go m.observe(m.observers.sigc)
+	}
+	if m.observers.byS == nil {
+		m.observers.byS = map[os.Signal][]*observer{}
+	}
+	if _, ok := m.observers.byS[sig]; !ok {
+		signal.Notify(m.observers.sigc, sig)
+		m.unignore(sig)
+	}
+
+	// Insert the observer after the observers with the same or a lower
+	// priority so that observers that share a priority are invoked in the
+	// order in which they were registered.
+	a := m.observers.byS[sig]
+	i := sort.Search(len(a), func(i int) bool {
+		return a[i].priority > o.priority
+	})
+	b := make([]*observer, 0, len(a)+1)
+	b = append(b, a[:i]...)
+	b = append(b, o)
+	m.observers.byS[sig] = append(b, a[i:]...)
+
+	return func() { m.unobserve(o) }
+}
+
+func (m *Manager) unobserve(o *observer) {
+	m.observers.Lock()
+	defer m.observers.Unlock()
+
+	a := m.observers.byS[o.sig]
+	for i := range a {
+		if a[i] != o {
+			continue
+		}
+		a = append(a[:i:i], a[i+1:]...)
+		if len(a) > 0 {
+			m.observers.byS[o.sig] = a
+			return
+		}
+
+		// Stop relaying the signal that is no longer observed. The os/signal
+		// package cannot stop relaying a single signal to a channel, so
+		// the channel is stopped and then notified of the remaining
+		// observed signals.
+		delete(m.observers.byS, o.sig)
+		signal.Stop(m.observers.sigc)
+		if sigs := m.observedSignals(); len(sigs) > 0 {
+			signal.Notify(m.observers.sigc, sigs...)
+		}
+		return
+	}
+}
+
+// observedSignals returns the list of observed signals. The caller must
+// hold the observers lock.
+func (m *Manager) observedSignals() []os.Signal {
+	sigs := make([]os.Signal, 0, len(m.observers.byS))
+	for s := range m.observers.byS {
+		sigs = append(sigs, s)
+	}
+	return sigs
+}
+
+// resetObservers removes all of the observers and stops relaying the
+// observed signals.
+func (m *Manager) resetObservers() {
+	m.observers.Lock()
+	defer m.observers.Unlock()
+	if m.observers.sigc != nil {
+		signal.Stop(m.observers.sigc)
+	}
+	m.observers.byS = nil
+}
+
+func (m *Manager) observe(sigc chan os.Signal) {
+	for s := range sigc {
+		if m.coalesce(s, true) {
+			continue
+		}
+		m.notifyObservers(s)
+	}
+}
+
+// notifyObservers invokes the observers of the signal.
+func (m *Manager) notifyObservers(s os.Signal) {
+	m.observers.RLock()
+	a := append([]*observer(nil), m.observers.byS[s]...)
+	m.observers.RUnlock()
+
+	ctx := m.context()
+	if len(a) > 0 {
+		m.lastSignal.set(s)
+		m.log(ctx, LevelInfo, MsgSignalObserved, KeySignal, s)
+	}
+	for _, o := range a {
+		o.f(ctx, s)
+	}
+	m.suspend(s, a)
+}
+
+// Observe registers a function to be invoked each time this process
+// receives the specified signal. Observing a signal does not cause the
+// process to exit when the signal is received, nor does it execute the
+// exit handlers. This is useful for signals such as SIGHUP that are used
+// to reload configuration or rotate logs.
+//
+// Observers are invoked in the order of their priority, and observers
+// that share a priority are invoked in the order in which they were
+// registered. The context provided to an observer is the one provided to
+// the Notify function or context.Background if Notify has not been
+// invoked. Please see the ObserveWithPriority function.
+//
+// The returned function removes the observer when invoked. It is safe to
+// invoke the returned function more than once.
+func Observe(sig os.Signal, f ObserverFunc) func() {
+	return defaultManager.Observe(sig, f)
+}
+
+// ObserveWithPriority registers a function to be invoked each time this
+// process receives the specified signal. Please see the Manager's
+// ObserveWithPriority function.
+func ObserveWithPriority(sig os.Signal, f ObserverFunc, priority int) func() {
+	return defaultManager.ObserveWithPriority(sig, f, priority)
+}
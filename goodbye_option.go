@@ -0,0 +1,88 @@
+// +build go1.8
+
+package goodbye
+
+import "time"
+
+// Option configures a Manager. Options may be provided to the Notify and
+// Exit functions.
+type Option func(m *Manager)
+
+// WithSignals returns an Option that specifies the signals trapped by the
+// Notify function. Please see the NotifySignals function for a description
+// of the specs.
+func WithSignals(specs ...SignalSpec) Option {
+	return func(m *Manager) {
+		m.configRWL.Lock()
+		defer m.configRWL.Unlock()
+		m.specs = specs
+	}
+}
+
+// WithGracePeriod returns an Option that sets the grace period. Please see
+// the SetGracePeriod function.
+func WithGracePeriod(d time.Duration) Option {
+	return func(m *Manager) {
+		m.SetGracePeriod(d)
+	}
+}
+
+// WithForcedExitCode returns an Option that sets the exit code used when
+// the grace period expires. Please see the SetForcedExitCode function.
+func WithForcedExitCode(exitCode int) Option {
+	return func(m *Manager) {
+		m.SetForcedExitCode(exitCode)
+	}
+}
+
+// WithSlowHandlerThreshold returns an Option that sets the amount of time
+// after which an exit handler that is still running is logged as slow.
+// Please see the SetSlowHandlerThreshold function.
+func WithSlowHandlerThreshold(d time.Duration) Option {
+	return func(m *Manager) {
+		m.SetSlowHandlerThreshold(d)
+	}
+}
+
+// WithErrorExitCode returns an Option that sets the exit code used when an
+// exit handler returns an error. Please see the SetErrorExitCode function.
+func WithErrorExitCode(exitCode int) Option {
+	return func(m *Manager) {
+		m.SetErrorExitCode(exitCode)
+	}
+}
+
+// WithParallelism returns an Option that sets the maximum number of exit
+// handlers that share a priority level that may be executed concurrently.
+// Please see the SetConcurrency function.
+func WithParallelism(n int) Option {
+	return func(m *Manager) {
+		m.SetConcurrency(n)
+	}
+}
+
+// WithEnv returns an Option that sets whether the Notify function reads
+// the environment variables that configure the Manager. Please see the
+// SetUseEnv function.
+func WithEnv(enabled bool) Option {
+	return func(m *Manager) {
+		m.SetUseEnv(enabled)
+	}
+}
+
+// WithExiter returns an Option that sets the function used to exit the
+// process. Please see the SetExiter function.
+func WithExiter(f func(code int)) Option {
+	return func(m *Manager) {
+		m.SetExiter(f)
+	}
+}
+
+// apply applies the options to the Manager.
+func (m *Manager) apply(opts []Option) {
+	for _, o := range opts {
+		if o != nil {
+			o(m)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"time"
+)
+
+// RegisterOptional registers a named function that returns an error to be
+// invoked when this process exits normally or due to a process signal,
+// unless the shutdown is running out of time. Optional handlers perform
+// work that is worthwhile but not essential, such as warming a cache for
+// the next instance or sending a farewell notification, so that the time
+// that remains is spent on the handlers that are not optional. Please see
+// RegisterWithPriority for a description of the priority.
+//
+// An optional handler is skipped if, when it would be executed, less time
+// than the threshold set with SetOptionalThreshold remains before the
+// deadline of the context provided to the exit handlers. A skipped handler
+// is logged with MsgHandlerSkipped and reported as HandlerSkipped.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterOptional(name string, f ExitFunc, priority int) func() {
+	return m.register(&handler{f: f, name: name, priority: priority, optional: true})
+}
+
+// SetOptionalThreshold sets the amount of time that must remain before
+// the deadline of the context provided to the exit handlers for the
+// optional handlers to be executed. The deadline is set by the grace period
+// and the timeout of the handler's priority level. A value of zero, the
+// default, means the optional handlers are always executed.
+func (m *Manager) SetOptionalThreshold(d time.Duration) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.optionalThreshold = d
+}
+
+// skipOptional returns true if the handler is optional and less time than
+// the optional threshold remains before the context's deadline.
+func (m *Manager) skipOptional(ctx context.Context, h *handler) bool {
+	if !h.optional {
+		return false
+	}
+	m.configRWL.RLock()
+	threshold := m.optionalThreshold
+	m.configRWL.RUnlock()
+	if threshold <= 0 {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) < threshold
+}
+
+// RegisterOptional registers a named function that returns an error to be
+// invoked when this process exits, unless the shutdown is running out of
+// time. Please see the Manager's RegisterOptional function.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func RegisterOptional(name string, f ExitFunc, priority int) func() {
+	return defaultManager.RegisterOptional(name, f, priority)
+}
+
+// SetOptionalThreshold sets the amount of time that must remain before the
+// exit handlers' deadline for the optional handlers to be executed. Please
+// see the Manager's SetOptionalThreshold function.
+func SetOptionalThreshold(d time.Duration) {
+	defaultManager.SetOptionalThreshold(d)
+}
+
+// WithOptionalThreshold returns an Option that sets the amount of time
+// that must remain before the exit handlers' deadline for the optional
+// handlers to be executed. Please see the SetOptionalThreshold function.
+func WithOptionalThreshold(d time.Duration) Option {
+	return func(m *Manager) {
+		m.SetOptionalThreshold(d)
+	}
+}
@@ -0,0 +1,43 @@
+// +build go1.8
+
+package goodbye
+
+// SetExitOwner enables the cooperative mode, in which the Manager executes
+// the exit handlers but does not exit the process. Instead the owner, ex.
+// the framework that controls the process's termination, is invoked with
+// the exit code once the handlers complete, the grace period expires, or
+// the shutdown is escalated, whichever happens first. The owner is invoked
+// at most once for each shutdown, and the Exit function returns once the
+// handlers complete. A nil owner disables the cooperative mode.
+//
+// Unlike the function set with SetExiter, which replaces os.Exit and may
+// be invoked more than once, the owner is responsible for terminating the
+// process, and the Manager continues to run after it is invoked.
+func (m *Manager) SetExitOwner(owner func(code int)) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.exitOwner = owner
+}
+
+// ownedExiter returns a function that invokes the exit owner at most once
+// for the shutdown cycle.
+func (c *cycle) ownedExiter(owner func(code int)) func(int) {
+	return func(code int) {
+		c.ownerOnce.Do(func() { owner(code) })
+	}
+}
+
+// SetExitOwner enables the cooperative mode, in which the exit handlers
+// are executed but the process does not exit. Please see the Manager's
+// SetExitOwner function.
+func SetExitOwner(owner func(code int)) {
+	defaultManager.SetExitOwner(owner)
+}
+
+// WithExitOwner returns an Option that enables the cooperative mode.
+// Please see the SetExitOwner function.
+func WithExitOwner(owner func(code int)) Option {
+	return func(m *Manager) {
+		m.SetExitOwner(owner)
+	}
+}
@@ -0,0 +1,36 @@
+// +build go1.8
+
+package goodbye
+
+// PageEvent is a browser event received by a WebAssembly program that is
+// compiled with GOOS=js. Page events are signals that may be trapped with
+// the Notify function, and the PageBeforeUnload and PageHide events are
+// trapped by default so that closing or navigating away from the page
+// executes the exit handlers. The process exits in the sense that the Go
+// program terminates; the page itself is not affected.
+//
+// Page events are never received on other platforms.
+type PageEvent string
+
+const (
+	// PageBeforeUnload is received when the page is about to be unloaded.
+	PageBeforeUnload PageEvent = "beforeunload"
+
+	// PageHide is received when the page is hidden as part of navigating
+	// away from it, including when it is placed in the back-forward cache.
+	PageHide PageEvent = "pagehide"
+
+	// PageHidden is received when the page's visibility state changes to
+	// hidden, ex. when the tab is switched or the browser is minimized. It
+	// is the last event that mobile browsers reliably deliver before a
+	// page is discarded, but the page may become visible again, so it is
+	// not trapped by default.
+	PageHidden PageEvent = "visibilitychange"
+)
+
+// Signal is a no-op that allows a PageEvent to be an os.Signal.
+func (e PageEvent) Signal() {}
+
+func (e PageEvent) String() string {
+	return string(e)
+}
@@ -0,0 +1,75 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+)
+
+// PanicPolicy describes how a Manager responds to an exit handler that
+// panics.
+type PanicPolicy int
+
+const (
+	// PanicContinue recovers the panic, records it as the handler's error,
+	// and continues executing the remaining handlers. It is the default.
+	PanicContinue PanicPolicy = iota
+
+	// PanicAbort recovers the panic, records it as the handler's error,
+	// and does not execute any handlers that have not yet been started.
+	// The process exits once the handlers that are running complete.
+	PanicAbort
+
+	// PanicRepanic records the panic and then panics again with the
+	// original value, which terminates the process.
+	PanicRepanic
+)
+
+// PanicError is the error recorded for an exit handler that panicked.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// invoke executes the handler's function. A panic is recovered and
+// returned as a PanicError.
+func invoke(ctx context.Context, s os.Signal, h *handler) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return h.f(ctx, s)
+}
+
+// SetPanicPolicy sets how the Manager responds to an exit handler that
+// panics. The default value is PanicContinue.
+func (m *Manager) SetPanicPolicy(p PanicPolicy) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.panicPolicy = p
+}
+
+// SetPanicPolicy sets how the package-level functions respond to an exit
+// handler that panics. The default value is PanicContinue.
+func SetPanicPolicy(p PanicPolicy) {
+	defaultManager.SetPanicPolicy(p)
+}
+
+// WithPanicPolicy returns an Option that sets how the Manager responds to
+// an exit handler that panics. Please see the SetPanicPolicy function.
+func WithPanicPolicy(p PanicPolicy) Option {
+	return func(m *Manager) {
+		m.SetPanicPolicy(p)
+	}
+}
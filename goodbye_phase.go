@@ -0,0 +1,172 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Phase is a named stage of the shutdown sequence. Phases are a shared
+// vocabulary for the priorities of exit handlers. The handlers registered
+// with a phase are executed at the phase's priority.
+type Phase struct {
+	// Name is the name of the phase.
+	Name string
+
+	// Priority is the priority at which the phase's handlers are executed.
+	Priority int
+}
+
+var (
+	// PhaseCritical is the phase in which the most important cleanup is
+	// performed, ex. flushing a write-ahead log or releasing a lock. It
+	// has the lowest possible priority and is executed before the pre-stop
+	// delay, the inhibitors, and the BeforeAll functions so that it may
+	// complete when the operating system allows the process only a few
+	// seconds to exit. Please see the RegisterCritical function.
+	PhaseCritical = Phase{Name: "critical", Priority: minPriority}
+
+	// PhaseDrain is the phase in which a process stops accepting new work
+	// and waits for in-flight work to complete. It is executed before the
+	// handlers registered with the default priority of 0.
+	PhaseDrain = Phase{Name: "drain", Priority: -1000}
+
+	// PhaseFlush is the phase in which buffered data is written. It is
+	// executed after the handlers registered with the default priority
+	// of 0.
+	PhaseFlush = Phase{Name: "flush", Priority: 1000}
+
+	// PhaseClose is the phase in which files, connections, and other
+	// resources are closed. It is executed after PhaseFlush.
+	PhaseClose = Phase{Name: "close", Priority: 2000}
+)
+
+var (
+	// phases is the list of phases by name. It is used to look up the
+	// phases referenced by a Config.
+	phases = map[string]Phase{
+		PhaseCritical.Name: PhaseCritical,
+		PhaseDrain.Name:    PhaseDrain,
+		PhaseFlush.Name:    PhaseFlush,
+		PhaseClose.Name:    PhaseClose,
+	}
+	phasesRWL sync.RWMutex
+)
+
+// NewPhase returns a custom phase with the specified name and priority.
+// Please see RegisterWithPriority for a description of the priority.
+//
+// The phase may be referenced by name in a Config. A phase created with
+// the name of an existing phase replaces it.
+func NewPhase(name string, priority int) Phase {
+	p := Phase{Name: name, Priority: priority}
+	phasesRWL.Lock()
+	defer phasesRWL.Unlock()
+	phases[name] = p
+	return p
+}
+
+// LookupPhase returns the phase with the specified name. The name may be
+// one of the predefined phases or a phase created with NewPhase.
+func LookupPhase(name string) (Phase, bool) {
+	phasesRWL.RLock()
+	defer phasesRWL.RUnlock()
+	p, ok := phases[name]
+	return p, ok
+}
+
+// String returns the phase's name, or its priority if the phase does not
+// have a name.
+func (p Phase) String() string {
+	if p.Name == "" {
+		return strconv.Itoa(p.Priority)
+	}
+	return p.Name
+}
+
+// RegisterPhase registers a function to be invoked during the specified
+// phase when this process exits normally or due to a process signal.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterPhase(phase Phase, f ExitHandler) func() {
+	return m.RegisterWithPriority(f, phase.Priority)
+}
+
+// RegisterPhaseFunc registers a function that returns an error to be
+// invoked during the specified phase when this process exits normally or
+// due to a process signal.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterPhaseFunc(phase Phase, f ExitFunc) func() {
+	return m.RegisterFuncWithPriority(f, phase.Priority)
+}
+
+// SetPhaseTimeout sets the amount of time the handlers of the specified
+// phase are given to complete. If the handlers are still running when the
+// timeout expires then the next phase or priority level is executed
+// without waiting for them, and the handlers that did not complete are
+// reported with an error that wraps ErrTimeout. The context provided to
+// the phase's handlers has a deadline at the end of the timeout. A value
+// of zero, the default, means the phase does not have a timeout.
+func (m *Manager) SetPhaseTimeout(phase Phase, d time.Duration) {
+	m.SetPriorityTimeout(phase.Priority, d)
+}
+
+// SetPriorityTimeout sets the amount of time the handlers of the specified
+// priority level are given to complete. It allows a time budget to be
+// assigned to a priority level that is not a phase. Please see the
+// SetPhaseTimeout function.
+//
+// A level whose timeout expires is recorded in the ShutdownReport's
+// Overruns field and logged with MsgLevelTimedOut. The timeouts do not
+// extend the grace period, which bounds the shutdown as a whole.
+func (m *Manager) SetPriorityTimeout(priority int, d time.Duration) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	if d <= 0 {
+		delete(m.timeouts, priority)
+		return
+	}
+	m.timeouts[priority] = d
+}
+
+// RegisterPhase registers a function to be invoked during the specified
+// phase when this process exits normally or due to a process signal.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func RegisterPhase(phase Phase, f ExitHandler) func() {
+	return defaultManager.RegisterPhase(phase, f)
+}
+
+// RegisterPhaseFunc registers a function that returns an error to be
+// invoked during the specified phase when this process exits normally or
+// due to a process signal.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func RegisterPhaseFunc(phase Phase, f ExitFunc) func() {
+	return defaultManager.RegisterPhaseFunc(phase, f)
+}
+
+// SetPhaseTimeout sets the amount of time the handlers of the specified
+// phase are given to complete. If the handlers are still running when the
+// timeout expires then the next phase or priority level is executed
+// without waiting for them, and the handlers that did not complete are
+// reported with an error that wraps ErrTimeout. The context provided to
+// the phase's handlers has a deadline at the end of the timeout. A value
+// of zero, the default, means the phase does not have a timeout.
+func SetPhaseTimeout(phase Phase, d time.Duration) {
+	defaultManager.SetPhaseTimeout(phase, d)
+}
+
+// SetPriorityTimeout sets the amount of time the handlers of the specified
+// priority level are given to complete. Please see the Manager's
+// SetPriorityTimeout function.
+func SetPriorityTimeout(priority int, d time.Duration) {
+	defaultManager.SetPriorityTimeout(priority, d)
+}
@@ -0,0 +1,36 @@
+// +build plan9
+
+package goodbye
+
+import (
+	"os"
+	"syscall"
+)
+
+// hangup is the note posted to a process when its connection is closed.
+const hangup = syscall.Note("hangup")
+
+func init() {
+	defaultSignals = map[os.Signal]int{
+		os.Interrupt: 0,
+		hangup:       0,
+	}
+	untrappableSignals = map[os.Signal]bool{
+		os.Kill: true,
+	}
+	signalNames = map[string]os.Signal{
+		"SIGHUP":  hangup,
+		"SIGINT":  os.Interrupt,
+		"SIGKILL": os.Kill,
+	}
+}
+
+// signalNumber returns false since notes do not have numbers.
+func signalNumber(s os.Signal) (int, bool) {
+	return 0, false
+}
+
+// numberedSignal returns false since notes do not have numbers.
+func numberedSignal(n int) (os.Signal, bool) {
+	return nil, false
+}
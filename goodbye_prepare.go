@@ -0,0 +1,173 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// PrepareFunc is a function that is registered with the RegisterPreparer
+// function and is invoked when a trapped signal is received, before the
+// shutdown begins. It returns a non-nil error to veto the shutdown.
+type PrepareFunc func(ctx context.Context, s os.Signal) error
+
+// VetoError is the error with which a preparer vetoed a shutdown.
+type VetoError struct {
+	// Name is the name of the preparer.
+	Name string
+
+	// Signal is the signal that would have caused the shutdown.
+	Signal os.Signal
+
+	// Err is the error returned by the preparer.
+	Err error
+}
+
+// Error returns the preparer's error prefixed by the preparer's name.
+func (e *VetoError) Error() string {
+	return fmt.Sprintf("goodbye: %s vetoed the shutdown: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the error returned by the preparer.
+func (e *VetoError) Unwrap() error {
+	return e.Err
+}
+
+// preparer is a registered PrepareFunc.
+type preparer struct {
+	name string
+	f    PrepareFunc
+}
+
+// preparers is the list of preparers in the order in which they were
+// registered and the state of the prepare phases that are in progress.
+type preparers struct {
+	a       []*preparer
+	pending map[*preparation]struct{}
+	sync.Mutex
+}
+
+// preparation is the state of a prepare phase that is in progress.
+type preparation struct {
+	cancel  context.CancelFunc
+	aborted bool
+}
+
+// RegisterPreparer registers a named function that is invoked when a
+// trapped signal is received, before the shutdown begins. A shutdown is
+// executed in two phases: in the prepare phase the preparers are invoked
+// in the order in which they were registered, and any of them may veto
+// the shutdown by returning an error, ex. because a backup is running.
+// Only if no preparer vetoes the shutdown does the commit phase execute
+// the exit handlers and exit the process.
+//
+// A vetoed shutdown is logged with MsgShutdownVetoed and reported to the
+// FeedbackFunc with a FeedbackVetoed event whose error is a *VetoError.
+// The Manager remains armed: the signal continues to be trapped, and a
+// later signal begins a new attempt to shut down. A shutdown whose prepare
+// phase is in progress may also be aborted with the Abort function. The
+// Exit functions are not subject to the preparers, and neither is a signal
+// that escalates a shutdown that is already in progress.
+//
+// The returned function removes the preparer when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterPreparer(name string, f PrepareFunc) func() {
+	p := &preparer{name: name, f: f}
+	m.preparers.Lock()
+	defer m.preparers.Unlock()
+	m.preparers.a = append(m.preparers.a, p)
+	return func() { m.unregisterPreparer(p) }
+}
+
+func (m *Manager) unregisterPreparer(p *preparer) {
+	m.preparers.Lock()
+	defer m.preparers.Unlock()
+	a := m.preparers.a
+	for i := range a {
+		if a[i] == p {
+			m.preparers.a = append(a[:i:i], a[i+1:]...)
+			return
+		}
+	}
+}
+
+// prepare invokes the preparers. It returns a *VetoError if a preparer
+// vetoes the shutdown, in which case the remaining preparers are not
+// invoked, or ErrAborted if the Abort function is invoked before the
+// preparers complete.
+func (m *Manager) prepare(ctx context.Context, s os.Signal) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	p := &preparation{cancel: cancel}
+
+	m.preparers.Lock()
+	a := append([]*preparer(nil), m.preparers.a...)
+	if m.preparers.pending == nil {
+		m.preparers.pending = map[*preparation]struct{}{}
+	}
+	m.preparers.pending[p] = struct{}{}
+	m.preparers.Unlock()
+
+	var err error
+	for _, pr := range a {
+		if perr := pr.f(ctx, s); perr != nil {
+			err = &VetoError{Name: pr.name, Signal: s, Err: perr}
+			break
+		}
+	}
+
+	m.preparers.Lock()
+	delete(m.preparers.pending, p)
+	if p.aborted {
+		err = ErrAborted
+	}
+	m.preparers.Unlock()
+	return err
+}
+
+// Abort aborts the shutdowns whose prepare phases are in progress. The
+// context provided to the preparers is canceled, and once they return the
+// shutdown is not committed: the exit handlers are not executed, the
+// signal continues to be trapped, and a later signal begins a new attempt
+// to shut down. An aborted shutdown is logged with MsgShutdownVetoed and
+// reported to the FeedbackFunc with a FeedbackVetoed event whose error is
+// ErrAborted.
+//
+// A shutdown cannot be aborted once its prepare phase has completed, which
+// is the point of no return. Abort returns false if no prepare phase was
+// in progress.
+func (m *Manager) Abort() bool {
+	m.preparers.Lock()
+	defer m.preparers.Unlock()
+	for p := range m.preparers.pending {
+		p.aborted = true
+		p.cancel()
+	}
+	return len(m.preparers.pending) > 0
+}
+
+// resetPreparers removes all of the preparers.
+func (m *Manager) resetPreparers() {
+	m.preparers.Lock()
+	defer m.preparers.Unlock()
+	m.preparers.a = nil
+}
+
+// RegisterPreparer registers a named function that is invoked when a
+// trapped signal is received and may veto the shutdown. Please see the
+// Manager's RegisterPreparer function.
+//
+// The returned function removes the preparer when invoked. It is safe to
+// invoke the returned function more than once.
+func RegisterPreparer(name string, f PrepareFunc) func() {
+	return defaultManager.RegisterPreparer(name, f)
+}
+
+// Abort aborts the shutdowns whose prepare phases are in progress. Please
+// see the Manager's Abort function.
+func Abort() bool {
+	return defaultManager.Abort()
+}
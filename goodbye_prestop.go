@@ -0,0 +1,55 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"time"
+)
+
+// SetPreStopDelay sets the amount of time the Manager waits after it
+// begins exiting and before it executes the exit handlers. The channel
+// returned by Done is closed, and readiness checks such as those of the
+// health package fail, at the beginning of the delay, which gives load
+// balancers time to stop routing traffic to the process before its
+// listeners are closed. The delay counts against the grace period, and
+// ends early if the grace period's deadline passes. A value of zero, the
+// default, means there is no delay.
+func (m *Manager) SetPreStopDelay(d time.Duration) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.preStopDelay = d
+}
+
+// preStop waits for the pre-stop delay or for the context to be done.
+func (m *Manager) preStop(ctx context.Context) {
+	m.configRWL.RLock()
+	d := m.preStopDelay
+	m.configRWL.RUnlock()
+	if d <= 0 {
+		return
+	}
+	m.log(ctx, LevelInfo, MsgPreStopDelay, KeyDuration, d)
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
+
+// SetPreStopDelay sets the amount of time the package-level functions wait
+// after they begin exiting and before they execute the exit handlers.
+// Please see the Manager's SetPreStopDelay function.
+func SetPreStopDelay(d time.Duration) {
+	defaultManager.SetPreStopDelay(d)
+}
+
+// WithPreStopDelay returns an Option that sets the amount of time the
+// Manager waits before it executes the exit handlers. Please see the
+// SetPreStopDelay function.
+func WithPreStopDelay(d time.Duration) Option {
+	return func(m *Manager) {
+		m.SetPreStopDelay(d)
+	}
+}
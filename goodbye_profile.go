@@ -0,0 +1,106 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"time"
+)
+
+// ProfileDumpAction is the name of the action registered by the
+// RegisterProfileDump function.
+const ProfileDumpAction = "profile dump"
+
+// dumpedProfiles are the profiles written each time a profile dump is
+// requested, in addition to the optional CPU profile.
+var dumpedProfiles = []string{"goroutine", "heap", "allocs", "block", "mutex"}
+
+// RegisterProfileDump registers an action that writes pprof profiles to
+// the specified directory each time this process receives the specified
+// signal, ex. SIGUSR2. The process does not exit. This allows a stuck or
+// misbehaving service to be inspected in production without restarting
+// it.
+//
+// The goroutine, heap, allocs, block, and mutex profiles are written
+// immediately. If cpu is greater than zero then a CPU profile is also
+// collected for that long in the background. Each file is named after the
+// profile, the process ID, and the time of the signal, ex.
+// "heap-1234-20060102T150405.pprof". The directory is created if it does
+// not exist. Errors are logged with MsgProfileDumpFailed.
+//
+// The returned function removes the action when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterProfileDump(sig os.Signal, dir string, cpu time.Duration) func() {
+	return m.RegisterAction(sig, ProfileDumpAction, func(ctx context.Context, s os.Signal) {
+		m.dumpProfiles(ctx, s, dir, cpu)
+	})
+}
+
+// dumpProfiles writes the profiles to the directory and starts the CPU
+// profile if cpu is greater than zero.
+func (m *Manager) dumpProfiles(ctx context.Context, s os.Signal, dir string, cpu time.Duration) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		m.log(ctx, LevelError, MsgProfileDumpFailed, KeySignal, s, KeyError, err)
+		return
+	}
+	suffix := fmt.Sprintf("-%d-%s.pprof",
+		os.Getpid(), time.Now().UTC().Format("20060102T150405"))
+
+	for _, name := range dumpedProfiles {
+		p := pprof.Lookup(name)
+		if p == nil {
+			continue
+		}
+		err := writeProfile(filepath.Join(dir, name+suffix), func(f *os.File) error {
+			return p.WriteTo(f, 0)
+		})
+		if err != nil {
+			m.log(ctx, LevelError, MsgProfileDumpFailed, KeySignal, s, KeyError, err)
+		}
+	}
+
+	if cpu <= 0 {
+		return
+	}
+
+	// The CPU profile is collected in the background so that the other
+	// observers of the signal are not delayed.
+	go func() {
+		err := writeProfile(filepath.Join(dir, "cpu"+suffix), func(f *os.File) error {
+			if err := pprof.StartCPUProfile(f); err != nil {
+				return err
+			}
+			time.Sleep(cpu)
+			pprof.StopCPUProfile()
+			return nil
+		})
+		if err != nil {
+			m.log(ctx, LevelError, MsgProfileDumpFailed, KeySignal, s, KeyError, err)
+		}
+	}()
+}
+
+// writeProfile creates the file at the specified path and invokes the
+// function to write a profile to it.
+func writeProfile(path string, write func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// RegisterProfileDump registers an action that writes pprof profiles to
+// the specified directory each time this process receives the specified
+// signal. Please see the Manager's RegisterProfileDump function.
+func RegisterProfileDump(sig os.Signal, dir string, cpu time.Duration) func() {
+	return defaultManager.RegisterProfileDump(sig, dir, cpu)
+}
@@ -0,0 +1,73 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// DefaultPanicExitCode is the default exit code used when a panic is
+// converted into a shutdown. It matches the exit code of a Go program that
+// terminates due to an unrecovered panic.
+const DefaultPanicExitCode = 2
+
+// RecoverAndExit converts a panic into a graceful shutdown. It must be
+// deferred directly, as early as possible in the function whose panics it
+// recovers, typically main:
+//
+//	defer goodbye.RecoverAndExit(ctx)
+//
+// If the function panics then the panic is recovered, the exit handlers
+// are executed, and the process exits with the panic exit code. The panic
+// is recorded as a *PanicError with the value and stack trace of the panic,
+// which is logged with MsgExitError, recorded as the Cause of the
+// ShutdownReport, and available to the exit handlers with the ExitError
+// function. If the function did not panic then RecoverAndExit does nothing.
+func (m *Manager) RecoverAndExit(ctx context.Context) {
+	if v := recover(); v != nil {
+		m.exitPanic(ctx, v, debug.Stack())
+	}
+}
+
+// SetPanicExitCode sets the exit code used when a panic is converted into a
+// shutdown by RecoverAndExit or Run. The default value is
+// DefaultPanicExitCode.
+func (m *Manager) SetPanicExitCode(exitCode int) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.panicExitCode = exitCode
+}
+
+// exitPanic executes the exit handlers due to a panic with the value and
+// stack trace.
+func (m *Manager) exitPanic(ctx context.Context, v interface{}, stack []byte) {
+	m.configRWL.RLock()
+	code := m.panicExitCode
+	m.configRWL.RUnlock()
+	m.exitErr(ctx, &PanicError{Value: v, Stack: stack}, code)
+}
+
+// RecoverAndExit converts a panic into a graceful shutdown. Please see the
+// Manager's RecoverAndExit function.
+func RecoverAndExit(ctx context.Context) {
+	// The panic must be recovered by the deferred function itself.
+	if v := recover(); v != nil {
+		defaultManager.exitPanic(ctx, v, debug.Stack())
+	}
+}
+
+// SetPanicExitCode sets the exit code used when a panic is converted into a
+// shutdown. The default value is DefaultPanicExitCode.
+func SetPanicExitCode(exitCode int) {
+	defaultManager.SetPanicExitCode(exitCode)
+}
+
+// WithPanicExitCode returns an Option that sets the exit code used when a
+// panic is converted into a shutdown. Please see the SetPanicExitCode
+// function.
+func WithPanicExitCode(exitCode int) Option {
+	return func(m *Manager) {
+		m.SetPanicExitCode(exitCode)
+	}
+}
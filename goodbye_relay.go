@@ -0,0 +1,80 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"os"
+	"sync"
+)
+
+// relays is the list of channels to which trapped signals are relayed.
+type relays struct {
+	byC map[chan<- os.Signal][]os.Signal
+	sync.RWMutex
+}
+
+// Relay relays the specified trapped signals to the channel, or all of the
+// trapped signals if none are specified. This allows a library that would
+// otherwise invoke signal.Notify and race the Manager for the signals to
+// receive them from the Manager instead. A signal is relayed when it is
+// received, before the exit handlers are executed, and signals that
+// escalate the shutdown are relayed while the exit handlers are running.
+//
+// As with signal.Notify, the Manager does not block sending to the
+// channel, so the channel should be buffered. Invoking Relay again with
+// the same channel replaces the signals relayed to it.
+func (m *Manager) Relay(ch chan<- os.Signal, sigs ...os.Signal) {
+	m.relays.Lock()
+	defer m.relays.Unlock()
+	if m.relays.byC == nil {
+		m.relays.byC = map[chan<- os.Signal][]os.Signal{}
+	}
+	m.relays.byC[ch] = append([]os.Signal{}, sigs...)
+}
+
+// StopRelay stops relaying signals to the channel.
+func (m *Manager) StopRelay(ch chan<- os.Signal) {
+	m.relays.Lock()
+	defer m.relays.Unlock()
+	delete(m.relays.byC, ch)
+}
+
+// relay sends the signal to the channels to which it is relayed.
+func (m *Manager) relay(s os.Signal) {
+	m.relays.RLock()
+	defer m.relays.RUnlock()
+	for ch, sigs := range m.relays.byC {
+		if !relayed(s, sigs) {
+			continue
+		}
+		select {
+		case ch <- s:
+		default:
+		}
+	}
+}
+
+// relayed returns true if the signal is in the list or the list is empty.
+func relayed(s os.Signal, sigs []os.Signal) bool {
+	if len(sigs) == 0 {
+		return true
+	}
+	for _, v := range sigs {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Relay relays the specified trapped signals to the channel, or all of the
+// trapped signals if none are specified. Please see the Manager's Relay
+// function.
+func Relay(ch chan<- os.Signal, sigs ...os.Signal) {
+	defaultManager.Relay(ch, sigs...)
+}
+
+// StopRelay stops relaying signals to the channel.
+func StopRelay(ch chan<- os.Signal) {
+	defaultManager.StopRelay(ch)
+}
@@ -0,0 +1,284 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// HandlerStatus describes the outcome of an exit handler.
+type HandlerStatus int
+
+const (
+	// HandlerOK indicates the handler completed without an error.
+	HandlerOK HandlerStatus = iota
+
+	// HandlerFailed indicates the handler returned an error.
+	HandlerFailed
+
+	// HandlerTimedOut indicates the handler was still running when its
+	// priority level's timeout or the grace period expired.
+	HandlerTimedOut
+
+	// HandlerSkipped indicates the handler was never executed.
+	HandlerSkipped
+
+	// HandlerPanicked indicates the handler panicked. The handler's error
+	// is a *PanicError.
+	HandlerPanicked
+)
+
+// String returns the name of the status.
+func (s HandlerStatus) String() string {
+	switch s {
+	case HandlerOK:
+		return "ok"
+	case HandlerFailed:
+		return "failed"
+	case HandlerTimedOut:
+		return "timeout"
+	case HandlerSkipped:
+		return "skipped"
+	case HandlerPanicked:
+		return "panicked"
+	}
+	return "unknown"
+}
+
+// HandlerReport describes the execution of a single exit handler.
+type HandlerReport struct {
+	// Name is the name of the handler, or an empty string if the handler
+	// is unnamed.
+	Name string
+
+	// Priority is the priority at which the handler was executed.
+	Priority int
+
+	// Duration is how long the handler ran. It is zero for skipped
+	// handlers.
+	Duration time.Duration
+
+	// Err is the error returned by the handler, if any.
+	Err error
+
+	// Status is the outcome of the handler.
+	Status HandlerStatus
+}
+
+// Overrun describes a priority level whose handlers did not complete before
+// the level's timeout expired.
+type Overrun struct {
+	// Priority is the priority level.
+	Priority int
+
+	// Timeout is the amount of time the level's handlers were given to
+	// complete.
+	Timeout time.Duration
+}
+
+// ShutdownReport describes the execution of the exit handlers.
+type ShutdownReport struct {
+	// Signal is the signal that caused the shutdown. It is the value
+	// returned by NormalExitSignal if the shutdown was caused by Exit.
+	Signal os.Signal
+
+	// ExitCode is the exit code with which the process is exiting.
+	ExitCode int
+
+	// Start is the time at which the shutdown began.
+	Start time.Time
+
+	// Duration is how long the exit handlers took to execute.
+	Duration time.Duration
+
+	// Forced is true if the grace period expired or a second signal
+	// escalated the shutdown before the exit handlers completed.
+	Forced bool
+
+	// Handlers describes each of the exit handlers in the order in which
+	// they were scheduled.
+	Handlers []HandlerReport
+
+	// Err is the error returned by the exit handlers, if any.
+	Err error
+
+	// Cause is the error provided to ExitErr, if any. It is a *PanicError
+	// if the shutdown was caused by a panic recovered by RecoverAndExit.
+	Cause error
+
+	// Overruns describes the priority levels whose timeouts expired, in
+	// the order in which they were executed.
+	Overruns []Overrun
+
+	// Leaks are the stack traces of the goroutines that were still
+	// running once the exit handlers completed. It is only set if leak
+	// detection is enabled with SetLeakDetection.
+	Leaks []string
+}
+
+// recorder records the execution of exit handlers in order to build a
+// ShutdownReport.
+type recorder struct {
+	start    time.Time
+	cause    error
+	policy   PanicPolicy
+	hooks    hooks
+	aborted  bool
+	leaks    []string
+	overruns []Overrun
+	order    []*handler
+	started  map[*handler]time.Time
+	results  map[*handler]*HandlerReport
+	sync.Mutex
+}
+
+func newRecorder(policy PanicPolicy) *recorder {
+	return &recorder{
+		start:   time.Now(),
+		policy:  policy,
+		started: map[*handler]time.Time{},
+		results: map[*handler]*HandlerReport{},
+	}
+}
+
+// schedule records the order in which handlers are executed.
+func (r *recorder) schedule(handlers []*handler) {
+	r.Lock()
+	defer r.Unlock()
+	r.order = append(r.order, handlers...)
+}
+
+func (r *recorder) begin(h *handler) time.Time {
+	r.Lock()
+	defer r.Unlock()
+	t := time.Now()
+	r.started[h] = t
+	return t
+}
+
+func (r *recorder) finish(h *handler, d time.Duration, err error) HandlerReport {
+	r.Lock()
+	defer r.Unlock()
+	if rep, ok := r.results[h]; ok {
+		return *rep
+	}
+	rep := &HandlerReport{
+		Name: h.name, Priority: h.priority, Duration: d, Status: HandlerOK,
+	}
+	if err != nil {
+		rep.Err, rep.Status = err, HandlerFailed
+		if he, ok := err.(*HandlerError); ok {
+			if _, ok := he.Err.(*PanicError); ok {
+				rep.Status = HandlerPanicked
+				if r.policy == PanicAbort {
+					r.aborted = true
+				}
+			}
+		}
+	}
+	r.results[h] = rep
+	return *rep
+}
+
+// isAborted returns true if a handler panicked and the PanicPolicy is
+// PanicAbort.
+func (r *recorder) isAborted() bool {
+	r.Lock()
+	defer r.Unlock()
+	return r.aborted
+}
+
+// skip records that a handler was skipped.
+func (r *recorder) skip(h *handler) {
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.results[h]; ok {
+		return
+	}
+	r.results[h] = &HandlerReport{
+		Name: h.name, Priority: h.priority, Status: HandlerSkipped,
+	}
+}
+
+// overrun records that the timeout of a priority level expired.
+func (r *recorder) overrun(priority int, d time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	r.overruns = append(r.overruns, Overrun{Priority: priority, Timeout: d})
+}
+
+// setLeaks records the stack traces of the leaked goroutines.
+func (r *recorder) setLeaks(stacks []string) {
+	r.Lock()
+	defer r.Unlock()
+	r.leaks = stacks
+}
+
+// abandon records that a handler timed out if it was started, or was
+// skipped if it was not.
+func (r *recorder) abandon(h *handler, err error) {
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.results[h]; ok {
+		return
+	}
+	rep := &HandlerReport{
+		Name: h.name, Priority: h.priority, Err: err, Status: HandlerSkipped,
+	}
+	if t, ok := r.started[h]; ok {
+		rep.Duration, rep.Status = time.Since(t), HandlerTimedOut
+	}
+	r.results[h] = rep
+}
+
+// report builds a ShutdownReport. Handlers that are still running are
+// reported as having timed out and handlers that have not been started are
+// reported as skipped.
+func (r *recorder) report(s os.Signal, x int, err error, forced bool) ShutdownReport {
+	r.Lock()
+	defer r.Unlock()
+	rep := ShutdownReport{
+		Signal:   s,
+		ExitCode: x,
+		Start:    r.start,
+		Duration: time.Since(r.start),
+		Forced:   forced,
+		Handlers: make([]HandlerReport, 0, len(r.order)),
+		Err:      err,
+		Cause:    r.cause,
+		Overruns: append([]Overrun(nil), r.overruns...),
+		Leaks:    r.leaks,
+	}
+	for _, h := range r.order {
+		if hr, ok := r.results[h]; ok {
+			rep.Handlers = append(rep.Handlers, *hr)
+			continue
+		}
+		hr := HandlerReport{
+			Name: h.name, Priority: h.priority, Status: HandlerSkipped,
+		}
+		if t, ok := r.started[h]; ok {
+			hr.Duration, hr.Status = time.Since(t), HandlerTimedOut
+		}
+		rep.Handlers = append(rep.Handlers, hr)
+	}
+	return rep
+}
+
+// OnComplete sets a function that is invoked with a ShutdownReport after
+// the exit handlers have been executed and before the process exits. The
+// function is also invoked if the grace period expires, in which case the
+// report's Forced field is true.
+func (m *Manager) OnComplete(f func(ShutdownReport)) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.onComplete = f
+}
+
+// OnComplete sets a function that is invoked with a ShutdownReport after
+// the exit handlers have been executed and before the process exits.
+func OnComplete(f func(ShutdownReport)) {
+	defaultManager.OnComplete(f)
+}
@@ -0,0 +1,212 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+// ReportFormat is the encoding used to write a ShutdownReport.
+type ReportFormat int
+
+const (
+	// ReportJSON writes the ShutdownReport as a single line of JSON.
+	ReportJSON ReportFormat = iota
+
+	// ReportNDJSON writes one line of JSON for each exit handler followed
+	// by a line for the shutdown. Each line has an "event" field whose
+	// value is either "handler" or "shutdown".
+	ReportNDJSON
+)
+
+// reportWriter is the destination of a ShutdownReport.
+type reportWriter struct {
+	w      io.Writer
+	path   string
+	format ReportFormat
+}
+
+// MarshalText returns the name of the status.
+func (s HandlerStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+type jsonHandlerReport struct {
+	Event    string        `json:"event,omitempty"`
+	Name     string        `json:"name,omitempty"`
+	Priority int           `json:"priority"`
+	Duration Duration      `json:"duration"`
+	Status   HandlerStatus `json:"status"`
+	Error    string        `json:"error,omitempty"`
+}
+
+type jsonOverrun struct {
+	Priority int      `json:"priority"`
+	Timeout  Duration `json:"timeout"`
+}
+
+type jsonShutdownReport struct {
+	Event    string              `json:"event,omitempty"`
+	Signal   string              `json:"signal"`
+	ExitCode int                 `json:"exit_code"`
+	Start    time.Time           `json:"start"`
+	Duration Duration            `json:"duration"`
+	Forced   bool                `json:"forced"`
+	Handlers []jsonHandlerReport `json:"handlers,omitempty"`
+	Error    string              `json:"error,omitempty"`
+	Cause    string              `json:"cause,omitempty"`
+	Stack    string              `json:"stack,omitempty"`
+	Overruns []jsonOverrun       `json:"overruns,omitempty"`
+	Leaks    []string            `json:"leaks,omitempty"`
+}
+
+func (r HandlerReport) json() jsonHandlerReport {
+	v := jsonHandlerReport{
+		Name:     r.Name,
+		Priority: r.Priority,
+		Duration: Duration(r.Duration),
+		Status:   r.Status,
+	}
+	if r.Err != nil {
+		v.Error = r.Err.Error()
+	}
+	return v
+}
+
+func (r ShutdownReport) json() jsonShutdownReport {
+	v := jsonShutdownReport{
+		ExitCode: r.ExitCode,
+		Start:    r.Start,
+		Duration: Duration(r.Duration),
+		Forced:   r.Forced,
+		Leaks:    r.Leaks,
+	}
+	if r.Signal != nil {
+		v.Signal = SignalName(r.Signal)
+	}
+	if r.Err != nil {
+		v.Error = r.Err.Error()
+	}
+	if r.Cause != nil {
+		v.Cause = r.Cause.Error()
+		if pe, ok := r.Cause.(*PanicError); ok {
+			v.Stack = string(pe.Stack)
+		}
+	}
+	for _, o := range r.Overruns {
+		v.Overruns = append(v.Overruns, jsonOverrun{
+			Priority: o.Priority, Timeout: Duration(o.Timeout),
+		})
+	}
+	return v
+}
+
+// MarshalJSON encodes the HandlerReport. The duration is encoded as a
+// string, ex. "1.5s", and the error as its message.
+func (r HandlerReport) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.json())
+}
+
+// MarshalJSON encodes the ShutdownReport. The signal is encoded by name,
+// the durations as strings, ex. "1.5s", and the errors as their messages.
+func (r ShutdownReport) MarshalJSON() ([]byte, error) {
+	v := r.json()
+	for _, h := range r.Handlers {
+		v.Handlers = append(v.Handlers, h.json())
+	}
+	return json.Marshal(v)
+}
+
+// WriteReport writes the ShutdownReport to w with the specified format.
+func WriteReport(w io.Writer, r ShutdownReport, format ReportFormat) error {
+	enc := json.NewEncoder(w)
+	if format != ReportNDJSON {
+		return enc.Encode(r)
+	}
+	for _, h := range r.Handlers {
+		v := h.json()
+		v.Event = "handler"
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	v := r.json()
+	v.Event = "shutdown"
+	return enc.Encode(v)
+}
+
+// SetReportWriter sets the writer to which the ShutdownReport is written
+// before the process exits. A nil writer disables the report.
+func (m *Manager) SetReportWriter(w io.Writer, format ReportFormat) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	if w == nil {
+		m.reportWriter = nil
+		return
+	}
+	m.reportWriter = &reportWriter{w: w, format: format}
+}
+
+// SetReportFile sets the path of the file to which the ShutdownReport is
+// appended before the process exits. The file is created if it does not
+// exist. An empty path disables the report.
+func (m *Manager) SetReportFile(path string, format ReportFormat) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	if path == "" {
+		m.reportWriter = nil
+		return
+	}
+	m.reportWriter = &reportWriter{path: path, format: format}
+}
+
+// writeReport writes the report to the configured destination. Errors are
+// logged since the process is about to exit.
+func (m *Manager) writeReport(ctx context.Context, rw *reportWriter, r ShutdownReport) {
+	w := rw.w
+	if rw.path != "" {
+		f, err := os.OpenFile(
+			rw.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			m.log(ctx, LevelError, MsgReportFailed, KeyError, err)
+			return
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := WriteReport(w, r, rw.format); err != nil {
+		m.log(ctx, LevelError, MsgReportFailed, KeyError, err)
+	}
+}
+
+// SetReportWriter sets the writer to which the ShutdownReport is written
+// before the process exits. A nil writer disables the report.
+func SetReportWriter(w io.Writer, format ReportFormat) {
+	defaultManager.SetReportWriter(w, format)
+}
+
+// SetReportFile sets the path of the file to which the ShutdownReport is
+// appended before the process exits. An empty path disables the report.
+func SetReportFile(path string, format ReportFormat) {
+	defaultManager.SetReportFile(path, format)
+}
+
+// WithReportWriter returns an Option that sets the writer to which the
+// ShutdownReport is written. Please see the SetReportWriter function.
+func WithReportWriter(w io.Writer, format ReportFormat) Option {
+	return func(m *Manager) {
+		m.SetReportWriter(w, format)
+	}
+}
+
+// WithReportFile returns an Option that sets the file to which the
+// ShutdownReport is appended. Please see the SetReportFile function.
+func WithReportFile(path string, format ReportFormat) Option {
+	return func(m *Manager) {
+		m.SetReportFile(path, format)
+	}
+}
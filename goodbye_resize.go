@@ -0,0 +1,39 @@
+// +build go1.8
+
+package goodbye
+
+import "os"
+
+// resizeSignal is the signal sent to a process when the size of its
+// controlling terminal changes. It is nil if the operating system does not
+// send such a signal.
+var resizeSignal os.Signal
+
+// OnResize registers a function to be invoked each time the size of this
+// process's controlling terminal changes, which is signaled with SIGWINCH.
+// This allows a terminal application to receive resize notifications from
+// the Manager that already owns the process's signal handling rather than
+// running its own signal.Notify loop.
+//
+// The priority determines when the function is invoked relative to the
+// other resize observers. Please see RegisterWithPriority for a
+// description of the priority.
+//
+// OnResize does nothing on operating systems that do not signal changes
+// to the size of the terminal.
+//
+// The returned function removes the observer when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) OnResize(f ObserverFunc, priority int) func() {
+	if resizeSignal == nil {
+		return func() {}
+	}
+	return m.ObserveWithPriority(resizeSignal, f, priority)
+}
+
+// OnResize registers a function to be invoked each time the size of this
+// process's controlling terminal changes. Please see the Manager's OnResize
+// function.
+func OnResize(f ObserverFunc, priority int) func() {
+	return defaultManager.OnResize(f, priority)
+}
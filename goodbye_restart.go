@@ -0,0 +1,68 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"os"
+)
+
+// restartKey is the context key that indicates the process restarts
+// instead of exiting.
+type restartKey struct{}
+
+// Restart executes all of the registered exit handlers and then replaces
+// the process with a new instance of its binary, with the same arguments
+// and environment, instead of exiting. On Windows, where a process cannot
+// be replaced, the new instance is started and the process exits. If the
+// new instance cannot be started then the process exits with the exit
+// code that would have been used by Exit.
+//
+// The handlers see a signal for which IsNormalExit returns true.
+func (m *Manager) Restart(ctx context.Context, opts ...Option) {
+	m.Exit(context.WithValue(ctx, restartKey{}, true), 0, opts...)
+}
+
+// RestartOnSignal registers an action that restarts the process with the
+// Restart function each time the signal is received, ex. SIGHUP.
+//
+// The returned function removes the action when invoked.
+func (m *Manager) RestartOnSignal(sig os.Signal) func() {
+	return m.RegisterAction(sig, "restart", func(ctx context.Context, s os.Signal) {
+		go m.Restart(ctx)
+	})
+}
+
+// IsRestart returns true if the exit handlers are being executed because
+// of the Restart function. Exit handlers may use it with the context they
+// are given.
+func IsRestart(ctx context.Context) bool {
+	v, _ := ctx.Value(restartKey{}).(bool)
+	return v
+}
+
+// restartExiter returns a function that restarts the process, or exits
+// with exit if the restart fails.
+func (m *Manager) restartExiter(ctx context.Context, exit func(int)) func(int) {
+	return func(code int) {
+		m.log(ctx, LevelInfo, MsgRestarting)
+		if err := reexec(); err != nil {
+			m.log(ctx, LevelError, MsgRestartFailed, KeyError, err)
+		}
+		exit(code)
+	}
+}
+
+// Restart executes all of the registered exit handlers and then replaces
+// the process with a new instance of its binary. Please see the Manager's
+// Restart function.
+func Restart(ctx context.Context, opts ...Option) {
+	defaultManager.Restart(ctx, opts...)
+}
+
+// RestartOnSignal registers an action that restarts the process each time
+// the signal is received. Please see the Manager's RestartOnSignal
+// function.
+func RestartOnSignal(sig os.Signal) func() {
+	return defaultManager.RestartOnSignal(sig)
+}
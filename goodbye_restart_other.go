@@ -0,0 +1,9 @@
+// +build js wasip1
+
+package goodbye
+
+// reexec returns ErrNotSupported since the operating system cannot execute
+// a new instance of the binary.
+func reexec() error {
+	return ErrNotSupported
+}
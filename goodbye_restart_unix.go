@@ -0,0 +1,18 @@
+// +build !windows,!js,!wasip1
+
+package goodbye
+
+import (
+	"os"
+	"syscall"
+)
+
+// reexec replaces the process with a new instance of its binary. It only
+// returns if the binary cannot be executed.
+func reexec() error {
+	path, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	return syscall.Exec(path, os.Args, os.Environ())
+}
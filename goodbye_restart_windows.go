@@ -0,0 +1,23 @@
+// +build windows
+
+package goodbye
+
+import (
+	"os"
+	"os/exec"
+)
+
+// reexec starts a new instance of the process's binary. Windows does not
+// support replacing a process, so the caller exits once it returns.
+func reexec() error {
+	path, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
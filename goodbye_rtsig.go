@@ -0,0 +1,89 @@
+package goodbye
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// RealtimeSignal returns the real-time signal at the specified offset from
+// SIGRTMIN, ex. RealtimeSignal(3) returns SIGRTMIN+3. Real-time signals are
+// only available on Linux, where they are commonly used as control
+// channels by process supervisors.
+//
+// SIGRTMIN is the value reported by the C library, which reserves the
+// first real-time signals for its own use, rather than the value reported
+// by the kernel.
+//
+// ErrNotSupported is returned on operating systems that do not support
+// real-time signals. An error of type SignalError that wraps
+// ErrUnknownSignal is returned if the offset is outside of the range of
+// the real-time signals.
+func RealtimeSignal(offset int) (os.Signal, error) {
+	if rtmin == 0 {
+		return nil, ErrNotSupported
+	}
+	if offset < 0 || offset > rtmax-rtmin {
+		return nil, &SignalError{
+			Signal: unknownSignal("SIGRTMIN+" + strconv.Itoa(offset)),
+			Err:    ErrUnknownSignal,
+		}
+	}
+	s, _ := numberedSignal(rtmin + offset)
+	return s, nil
+}
+
+// parseRealtimeSignal returns the real-time signal with the specified
+// upper-case name. The name may be specified with or without the "SIG"
+// prefix and as an offset from either end of the range of the real-time
+// signals, ex. "SIGRTMIN", "SIGRTMIN+3", "RTMAX-2".
+func parseRealtimeSignal(upper string) (os.Signal, bool) {
+	if rtmin == 0 {
+		return nil, false
+	}
+	name := strings.TrimPrefix(upper, "SIG")
+
+	var base, sign int
+	switch {
+	case strings.HasPrefix(name, "RTMIN"):
+		base, sign, name = rtmin, 1, name[len("RTMIN"):]
+	case strings.HasPrefix(name, "RTMAX"):
+		base, sign, name = rtmax, -1, name[len("RTMAX"):]
+	default:
+		return nil, false
+	}
+
+	var offset int
+	if name != "" {
+		if (sign > 0 && name[0] != '+') || (sign < 0 && name[0] != '-') {
+			return nil, false
+		}
+		n, err := strconv.Atoi(name[1:])
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		offset = n
+	}
+	n := base + sign*offset
+	if n < rtmin || n > rtmax {
+		return nil, false
+	}
+	return numberedSignal(n)
+}
+
+// realtimeSignalName returns the name of the real-time signal, ex.
+// "SIGRTMIN+3". The returned boolean is false if the signal is not a
+// real-time signal.
+func realtimeSignalName(s os.Signal) (string, bool) {
+	if rtmin == 0 || s == nil {
+		return "", false
+	}
+	n, ok := signalNumber(s)
+	if !ok || n < rtmin || n > rtmax {
+		return "", false
+	}
+	if n == rtmin {
+		return "SIGRTMIN", true
+	}
+	return "SIGRTMIN+" + strconv.Itoa(n-rtmin), true
+}
@@ -0,0 +1,11 @@
+// +build linux
+
+package goodbye
+
+// rtmin and rtmax are the numbers of the first and last real-time signals
+// available to the process. The C library reserves signals 32 and 33 for
+// its threading implementation, so SIGRTMIN is 34.
+const (
+	rtmin = 34
+	rtmax = 64
+)
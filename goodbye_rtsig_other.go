@@ -0,0 +1,10 @@
+// +build !linux
+
+package goodbye
+
+// rtmin and rtmax are zero since real-time signals are not supported on the
+// operating system.
+const (
+	rtmin = 0
+	rtmax = 0
+)
@@ -0,0 +1,53 @@
+// +build go1.8
+
+package goodbye
+
+import "context"
+
+// Run owns the lifecycle of a program. It traps the signals with the
+// Notify function, invokes main with a context that is canceled when a
+// trapped signal is received, and then executes the exit handlers exactly
+// once and exits the process with the exit code returned by main:
+//
+//	func main() {
+//		goodbye.Run(func(ctx context.Context) int {
+//			if err := serve(ctx); err != nil {
+//				log.Print(err)
+//				return 1
+//			}
+//			return 0
+//		}, goodbye.WithGracePeriod(30*time.Second))
+//	}
+//
+// This replaces the pattern of deferring Exit and invoking Notify at the
+// start of main. The options are provided to the Notify function, so
+// WithSignals specifies the trapped signals and the default signals are
+// trapped otherwise.
+//
+// If Notify returns an error then the exit handlers are executed with
+// ExitErr. If main panics then the panic is converted into a shutdown with
+// RecoverAndExit. If a trapped signal is received then the process exits
+// once the exit handlers complete, regardless of whether main has
+// returned. Run only returns if the function set with SetExiter returns.
+func (m *Manager) Run(main func(ctx context.Context) int, opts ...Option) {
+	ctx := context.Background()
+	args := make([]interface{}, len(opts))
+	for i, o := range opts {
+		args[i] = o
+	}
+	if err := m.Notify(ctx, args...); err != nil {
+		m.ExitErr(ctx, err)
+		return
+	}
+
+	mctx, cancel := m.Context(ctx)
+	defer cancel()
+	defer m.RecoverAndExit(ctx)
+	m.Exit(ctx, main(mctx))
+}
+
+// Run owns the lifecycle of a program. Please see the Manager's Run
+// function.
+func Run(main func(ctx context.Context) int, opts ...Option) {
+	defaultManager.Run(main, opts...)
+}
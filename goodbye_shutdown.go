@@ -0,0 +1,87 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"sync"
+)
+
+// shutdownKey is the context key of the shutdownResult that indicates the
+// exit handlers are executed by the Shutdown function and the process does
+// not exit.
+type shutdownKey struct{}
+
+// shutdownResult records the outcome of a shutdown executed by the Shutdown
+// function.
+type shutdownResult struct {
+	once   sync.Once
+	done   chan struct{}
+	report *ShutdownReport
+}
+
+// exit records that the shutdown has completed instead of exiting the
+// process.
+func (r *shutdownResult) exit(code int) {
+	r.once.Do(func() { close(r.done) })
+}
+
+// onComplete returns a function that records the ShutdownReport and then
+// invokes next, if it is not nil.
+func (r *shutdownResult) onComplete(next func(ShutdownReport)) func(ShutdownReport) {
+	return func(rep ShutdownReport) {
+		r.report = &rep
+		if next != nil {
+			next(rep)
+		}
+	}
+}
+
+// shutdownResultFrom returns the shutdownResult in the context, or nil if
+// the exit handlers are not executed by the Shutdown function.
+func shutdownResultFrom(ctx context.Context) *shutdownResult {
+	r, _ := ctx.Value(shutdownKey{}).(*shutdownResult)
+	return r
+}
+
+// Shutdown executes all of the registered exit handlers, just as the Exit
+// function does, but returns instead of exiting the process. It is intended
+// for programs that must not terminate the process they are embedded in,
+// such as a server in a test harness, a plugin, or a tenant of a multi-
+// tenant host.
+//
+// Shutdown returns the error returned by the exit handlers, if any. If the
+// grace period expires or the shutdown is escalated before the handlers
+// complete then Shutdown returns ErrTimeout without waiting for the
+// handlers that are still running. If a shutdown has already been executed
+// then Shutdown returns ErrShutdownStarted; the Reset function re-arms the
+// Manager, even if handlers are still running after the grace period.
+//
+// Because the process does not exit, the watchdog set with SetHangTimeout
+// is not armed and the grace period's stack dump is not written.
+//
+// The handlers see a signal for which IsNormalExit returns true.
+func (m *Manager) Shutdown(ctx context.Context, opts ...Option) error {
+	r := &shutdownResult{done: make(chan struct{})}
+	ctx = context.WithValue(ctx, shutdownKey{}, r)
+	go func() {
+		m.Exit(ctx, 0, opts...)
+		r.exit(0)
+	}()
+	<-r.done
+
+	switch {
+	case r.report == nil:
+		return ErrShutdownStarted
+	case r.report.Forced:
+		return ErrTimeout
+	}
+	return r.report.Err
+}
+
+// Shutdown executes all of the registered exit handlers and returns
+// instead of exiting the process. Please see the Manager's Shutdown
+// function.
+func Shutdown(ctx context.Context, opts ...Option) error {
+	return defaultManager.Shutdown(ctx, opts...)
+}
@@ -0,0 +1,108 @@
+package goodbye
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// signalNames maps the names of the signals available on the operating
+// system to the signals. The names are upper-case and include the "SIG"
+// prefix, ex. "SIGTERM".
+var signalNames map[string]os.Signal
+
+// ParseSignal returns the signal with the specified name. The name is
+// case-insensitive and may be specified with or without the "SIG" prefix,
+// ex. "SIGTERM" or "term". The name may also be the signal's description
+// as returned by its String function, ex. "interrupt", or the number of a
+// signal available on the operating system, ex. "15". On Linux the name may also be a real-time signal
+// specified as an offset from SIGRTMIN or SIGRTMAX, ex. "SIGRTMIN+3" or
+// "RTMAX-2".
+//
+// An error of type SignalError that wraps ErrUnknownSignal is returned if
+// the name does not match a signal available on the operating system.
+func ParseSignal(name string) (os.Signal, error) {
+	name = strings.TrimSpace(name)
+	upper := strings.ToUpper(name)
+	if s, ok := signalNames[upper]; ok {
+		return s, nil
+	}
+	if s, ok := signalNames["SIG"+upper]; ok {
+		return s, nil
+	}
+	if s, ok := parseRealtimeSignal(upper); ok {
+		return s, nil
+	}
+	for _, s := range signalNames {
+		if strings.EqualFold(s.String(), name) {
+			return s, nil
+		}
+	}
+	if n, err := strconv.Atoi(name); err == nil && n > 0 {
+		if s, ok := numberedSignal(n); ok {
+			return s, nil
+		}
+	}
+	return nil, &SignalError{Signal: unknownSignal(name), Err: ErrUnknownSignal}
+}
+
+// SignalName returns the name of the signal, ex. "SIGTERM" or
+// "SIGRTMIN+3", or the result of the signal's String function if the
+// signal does not have a name.
+func SignalName(s os.Signal) string {
+	for name, v := range signalNames {
+		if v == s {
+			return name
+		}
+	}
+	if name, ok := realtimeSignalName(s); ok {
+		return name
+	}
+	if s == nil {
+		return "<nil>"
+	}
+	return s.String()
+}
+
+// unknownSignal is the signal value of a SignalError for a signal name
+// that could not be parsed.
+type unknownSignal string
+
+func (s unknownSignal) String() string {
+	return string(s)
+}
+func (s unknownSignal) Signal() {
+}
+
+// ParseSignalSpecs parses a list of signal specs separated by commas or
+// white space. Each spec is a signal name optionally followed by an equal
+// sign and the exit code to use when the signal is received, ex.
+// "SIGTERM=0,SIGINT=130,SIGHUP". Please see the ParseSignal function for
+// the supported signal names. The exit code defaults to zero.
+func ParseSignalSpecs(text string) ([]SignalSpec, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+	specs := make([]SignalSpec, 0, len(fields))
+	for _, f := range fields {
+		var (
+			name = f
+			code = 0
+		)
+		if i := strings.IndexByte(f, '='); i >= 0 {
+			name = f[:i]
+			c, err := strconv.Atoi(f[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("goodbye: invalid exit code: %s", f)
+			}
+			code = c
+		}
+		s, err := ParseSignal(name)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, SignalSpec{Signal: s, ExitCode: code})
+	}
+	return specs, nil
+}
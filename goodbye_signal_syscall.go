@@ -0,0 +1,31 @@
+// +build !plan9
+
+package goodbye
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalNumber returns the number of the signal. The returned boolean is
+// false if the signal does not have a number.
+func signalNumber(s os.Signal) (int, bool) {
+	n, ok := s.(syscall.Signal)
+	return int(n), ok
+}
+
+// numberedSignal returns the signal with the specified number. The
+// returned boolean is false if the number is not that of a signal
+// available on the operating system.
+func numberedSignal(n int) (os.Signal, bool) {
+	s := syscall.Signal(n)
+	if rtmin != 0 && n >= rtmin && n <= rtmax {
+		return s, true
+	}
+	for _, v := range signalNames {
+		if v == s {
+			return s, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,26 @@
+// +build go1.8
+
+package goodbye
+
+import "os"
+
+// SetSignalExitCode sets the exit code with which the process exits when
+// the signal is received. The signal must be a signal trapped by the
+// Manager, otherwise a SignalError that wraps ErrNotTrapped is returned. The exit codes of other signals are not
+// affected, and the signals do not need to be trapped again.
+func (m *Manager) SetSignalExitCode(sig os.Signal, exitCode int) error {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	if _, ok := m.trapped[sig]; !ok {
+		return &SignalError{Signal: sig, Err: ErrNotTrapped}
+	}
+	m.trapped[sig] = exitCode
+	return nil
+}
+
+// SetSignalExitCode sets the exit code with which the process exits when
+// the signal is received. Please see the Manager's SetSignalExitCode
+// function.
+func SetSignalExitCode(sig os.Signal, exitCode int) error {
+	return defaultManager.SetSignalExitCode(sig, exitCode)
+}
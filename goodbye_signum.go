@@ -0,0 +1,61 @@
+// +build go1.8
+
+package goodbye
+
+import "os"
+
+// shellExitCodeBase is added to a signal's number to get the exit code
+// used by shells for processes terminated by the signal.
+const shellExitCodeBase = 128
+
+// ShellExitCode returns 128 plus the number of the signal, which is the
+// exit status reported by shells for a process terminated by the signal.
+// The returned boolean is false if the signal does not have a number.
+func ShellExitCode(s os.Signal) (int, bool) {
+	n, ok := signalNumber(s)
+	if !ok {
+		return 0, false
+	}
+	return shellExitCodeBase + n, true
+}
+
+// SetShellExitCodes sets whether the process exits with 128 plus the
+// number of a trapped signal when the signal is received, matching the
+// convention used by shells, instead of with the exit code specified
+// when the signal was trapped. It is disabled by default.
+func (m *Manager) SetShellExitCodes(enabled bool) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.shellExitCodes = enabled
+}
+
+// signalExitCode returns the exit code for a trapped signal. The code is
+// x unless shell exit codes are enabled.
+func (m *Manager) signalExitCode(s os.Signal, x int) int {
+	m.configRWL.RLock()
+	enabled := m.shellExitCodes
+	m.configRWL.RUnlock()
+	if !enabled {
+		return x
+	}
+	if code, ok := ShellExitCode(s); ok {
+		return code
+	}
+	return x
+}
+
+// SetShellExitCodes sets whether the process exits with 128 plus the
+// number of a trapped signal when the signal is received. Please see the
+// Manager's SetShellExitCodes function.
+func SetShellExitCodes(enabled bool) {
+	defaultManager.SetShellExitCodes(enabled)
+}
+
+// WithShellExitCodes returns an Option that sets whether the process exits
+// with 128 plus the number of a trapped signal. Please see the
+// SetShellExitCodes function.
+func WithShellExitCodes(enabled bool) Option {
+	return func(m *Manager) {
+		m.SetShellExitCodes(enabled)
+	}
+}
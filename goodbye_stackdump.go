@@ -0,0 +1,188 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"io"
+	"os"
+	"runtime/pprof"
+)
+
+// quitSignal is the signal for which the Go runtime writes a goroutine
+// stack dump when the signal is not trapped. It is nil if the operating
+// system does not deliver such a signal.
+var quitSignal os.Signal
+
+// stackDump is the destination of the goroutine stack dump written when
+// SIGQUIT is received.
+type stackDump struct {
+	w    io.Writer
+	path string
+}
+
+// SetQuitStackDump sets the writer to which a stack dump of all goroutines
+// is written when SIGQUIT is received, before the exit handlers are
+// executed. A program that does not trap SIGQUIT gets such a dump from the
+// Go runtime, but trapping it for a graceful shutdown otherwise discards
+// that debugging information. A nil writer, the default, disables the
+// stack dump.
+func (m *Manager) SetQuitStackDump(w io.Writer) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	if w == nil {
+		m.stackDump = nil
+		return
+	}
+	m.stackDump = &stackDump{w: w}
+}
+
+// SetQuitStackDumpFile sets the path of the file to which a stack dump of
+// all goroutines is appended when SIGQUIT is received. The file is created
+// if it does not exist. An empty path disables the stack dump.
+func (m *Manager) SetQuitStackDumpFile(path string) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	if path == "" {
+		m.stackDump = nil
+		return
+	}
+	m.stackDump = &stackDump{path: path}
+}
+
+// dumpStacks writes a stack dump of all goroutines to the configured
+// destination if the signal is SIGQUIT.
+func (m *Manager) dumpStacks(ctx context.Context, s os.Signal) {
+	if quitSignal == nil || s != quitSignal {
+		return
+	}
+	m.configRWL.RLock()
+	sd := m.stackDump
+	m.configRWL.RUnlock()
+	if sd != nil {
+		m.writeStackDump(ctx, s, sd, "")
+	}
+}
+
+// writeStackDump writes the header, if any, followed by a stack dump of all
+// goroutines to the destination. Errors are logged since the process is
+// about to exit.
+func (m *Manager) writeStackDump(ctx context.Context, s os.Signal, sd *stackDump, header string) {
+	w := sd.w
+	if sd.path != "" {
+		f, err := os.OpenFile(
+			sd.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			m.log(ctx, LevelError, MsgStackDumpFailed, KeySignal, s, KeyError, err)
+			return
+		}
+		defer f.Close()
+		w = f
+	}
+	if header != "" {
+		if _, err := io.WriteString(w, header+"\n\n"); err != nil {
+			m.log(ctx, LevelError, MsgStackDumpFailed, KeySignal, s, KeyError, err)
+			return
+		}
+	}
+
+	// A debug level of 2 writes the stacks in the same format the Go
+	// runtime uses for an unrecovered panic or an untrapped SIGQUIT.
+	if err := pprof.Lookup("goroutine").WriteTo(w, 2); err != nil {
+		m.log(ctx, LevelError, MsgStackDumpFailed, KeySignal, s, KeyError, err)
+	}
+}
+
+// SetGracePeriodStackDump sets the writer to which a stack dump of all
+// goroutines is written when the grace period expires with exit handlers
+// still running, before the process is forcibly exited. The dump begins
+// with the names of the handlers that did not complete, and shows what
+// was blocking the shutdown. The default writer is os.Stderr. A nil writer
+// disables the stack dump.
+func (m *Manager) SetGracePeriodStackDump(w io.Writer) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	if w == nil {
+		m.gracePeriodStackDump = nil
+		return
+	}
+	m.gracePeriodStackDump = &stackDump{w: w}
+}
+
+// SetGracePeriodStackDumpFile sets the path of the file to which a stack
+// dump of all goroutines is appended when the grace period expires with
+// exit handlers still running. The file is created if it does not exist.
+// An empty path disables the stack dump.
+func (m *Manager) SetGracePeriodStackDumpFile(path string) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	if path == "" {
+		m.gracePeriodStackDump = nil
+		return
+	}
+	m.gracePeriodStackDump = &stackDump{path: path}
+}
+
+// SetQuitStackDump sets the writer to which a stack dump of all goroutines
+// is written when SIGQUIT is received. Please see the Manager's
+// SetQuitStackDump function.
+func SetQuitStackDump(w io.Writer) {
+	defaultManager.SetQuitStackDump(w)
+}
+
+// SetQuitStackDumpFile sets the path of the file to which a stack dump of
+// all goroutines is appended when SIGQUIT is received. Please see the
+// Manager's SetQuitStackDumpFile function.
+func SetQuitStackDumpFile(path string) {
+	defaultManager.SetQuitStackDumpFile(path)
+}
+
+// WithQuitStackDump returns an Option that sets the writer to which a
+// stack dump of all goroutines is written when SIGQUIT is received. Please
+// see the SetQuitStackDump function.
+func WithQuitStackDump(w io.Writer) Option {
+	return func(m *Manager) {
+		m.SetQuitStackDump(w)
+	}
+}
+
+// WithQuitStackDumpFile returns an Option that sets the file to which a
+// stack dump of all goroutines is appended when SIGQUIT is received.
+// Please see the SetQuitStackDumpFile function.
+func WithQuitStackDumpFile(path string) Option {
+	return func(m *Manager) {
+		m.SetQuitStackDumpFile(path)
+	}
+}
+
+// SetGracePeriodStackDump sets the writer to which a stack dump of all
+// goroutines is written when the grace period expires. Please see the
+// Manager's SetGracePeriodStackDump function.
+func SetGracePeriodStackDump(w io.Writer) {
+	defaultManager.SetGracePeriodStackDump(w)
+}
+
+// SetGracePeriodStackDumpFile sets the path of the file to which a stack
+// dump of all goroutines is appended when the grace period expires. Please
+// see the Manager's SetGracePeriodStackDumpFile function.
+func SetGracePeriodStackDumpFile(path string) {
+	defaultManager.SetGracePeriodStackDumpFile(path)
+}
+
+// WithGracePeriodStackDump returns an Option that sets the writer to which
+// a stack dump of all goroutines is written when the grace period expires.
+// Please see the SetGracePeriodStackDump function.
+func WithGracePeriodStackDump(w io.Writer) Option {
+	return func(m *Manager) {
+		m.SetGracePeriodStackDump(w)
+	}
+}
+
+// WithGracePeriodStackDumpFile returns an Option that sets the file to
+// which a stack dump of all goroutines is appended when the grace period
+// expires. Please see the SetGracePeriodStackDumpFile function.
+func WithGracePeriodStackDumpFile(path string) Option {
+	return func(m *Manager) {
+		m.SetGracePeriodStackDumpFile(path)
+	}
+}
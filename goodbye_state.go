@@ -0,0 +1,138 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"expvar"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Snapshot is a snapshot of the state of a Manager.
+type Snapshot struct {
+	// Trapped is the list of signals trapped as a result of the Notify
+	// functions, sorted by name.
+	Trapped []SignalSpec
+
+	// Ignored is the list of signals ignored with the Ignore function,
+	// sorted by name.
+	Ignored []os.Signal
+
+	// Observed is the list of observed signals, sorted by name.
+	Observed []os.Signal
+
+	// Handlers is the number of registered exit handlers by priority.
+	Handlers map[int]int
+
+	// ShuttingDown is true once a trapped signal has been received or the
+	// Exit function has been invoked.
+	ShuttingDown bool
+
+	// LastSignal is the most recent trapped or observed signal that was
+	// received, or nil if no signal has been received.
+	LastSignal os.Signal
+}
+
+// lastSignal records the most recent signal that was received.
+type lastSignal struct {
+	s os.Signal
+	sync.RWMutex
+}
+
+func (l *lastSignal) set(s os.Signal) {
+	l.Lock()
+	defer l.Unlock()
+	l.s = s
+}
+
+func (l *lastSignal) get() os.Signal {
+	l.RLock()
+	defer l.RUnlock()
+	return l.s
+}
+
+// State returns a snapshot of the state of the Manager.
+func (m *Manager) State() Snapshot {
+	var st Snapshot
+
+	m.configRWL.RLock()
+	for s, x := range m.trapped {
+		st.Trapped = append(st.Trapped, SignalSpec{Signal: s, ExitCode: x})
+	}
+	for s := range m.ignored {
+		st.Ignored = append(st.Ignored, s)
+	}
+	m.configRWL.RUnlock()
+	sort.Slice(st.Trapped, func(i, j int) bool {
+		return SignalName(st.Trapped[i].Signal) < SignalName(st.Trapped[j].Signal)
+	})
+	sort.Slice(st.Ignored, func(i, j int) bool {
+		return SignalName(st.Ignored[i]) < SignalName(st.Ignored[j])
+	})
+
+	m.observers.RLock()
+	st.Observed = m.observedSignals()
+	m.observers.RUnlock()
+	sort.Slice(st.Observed, func(i, j int) bool {
+		return SignalName(st.Observed[i]) < SignalName(st.Observed[j])
+	})
+
+	m.handlersRWL.RLock()
+	st.Handlers = make(map[int]int, len(m.handlers))
+	for p, a := range m.handlers {
+		st.Handlers[p] = len(a)
+	}
+	m.handlersRWL.RUnlock()
+
+	st.ShuttingDown = m.IsShuttingDown()
+	st.LastSignal = m.lastSignal.get()
+	return st
+}
+
+// PublishExpvar publishes the Manager's Snapshot as an expvar variable with
+// the specified name. The signals are published by name. As with the
+// expvar.Publish function, PublishExpvar panics if a variable with the
+// name is already published.
+func (m *Manager) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		st := m.State()
+		trapped := map[string]int{}
+		for _, spec := range st.Trapped {
+			trapped[SignalName(spec.Signal)] = spec.ExitCode
+		}
+		observed := make([]string, len(st.Observed))
+		for i, s := range st.Observed {
+			observed[i] = SignalName(s)
+		}
+		ignored := make([]string, len(st.Ignored))
+		for i, s := range st.Ignored {
+			ignored[i] = SignalName(s)
+		}
+		var last string
+		if st.LastSignal != nil {
+			last = SignalName(st.LastSignal)
+		}
+		return map[string]interface{}{
+			"trapped":       trapped,
+			"observed":      observed,
+			"ignored":       ignored,
+			"handlers":      st.Handlers,
+			"shutting_down": st.ShuttingDown,
+			"last_signal":   last,
+		}
+	}))
+}
+
+// State returns a snapshot of the state of the package-level functions.
+func State() Snapshot {
+	return defaultManager.State()
+}
+
+// PublishExpvar publishes the Snapshot of the package-level functions as an
+// expvar variable with the specified name. As with the expvar.Publish
+// function, PublishExpvar panics if a variable with the name is already
+// published.
+func PublishExpvar(name string) {
+	defaultManager.PublishExpvar(name)
+}
@@ -0,0 +1,93 @@
+// +build go1.8
+
+package goodbye
+
+import "os"
+
+// SetStrict sets whether signals are validated strictly. By default, the
+// Notify functions reject only the signals that cannot be trapped, such
+// as SIGKILL and SIGSTOP, and the other functions that accept a signal
+// silently accept any signal. When strict validation is enabled:
+//
+//   - The Notify functions and ApplyConfig also return a SignalError that
+//     wraps ErrUnknownSignal for a signal that is not available on the
+//     operating system, ex. syscall.Signal(99) or a console event on an
+//     operating system other than Windows.
+//
+//   - Observe, RegisterAction, RestartOnSignal, and RegisterForSignal do
+//     not register anything for a signal that cannot be trapped or is not
+//     available, and log MsgInvalidSignal with the error instead.
+//
+// Strict validation ensures that a program is not misled about which
+// signals its handlers cover.
+func (m *Manager) SetStrict(enabled bool) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.strict = enabled
+}
+
+// validateSignal returns an error if the signal cannot be trapped or, when
+// strict validation is enabled, if it is not available on the operating
+// system.
+func (m *Manager) validateSignal(s os.Signal) error {
+	if err := validateSignal(s); err != nil {
+		return err
+	}
+	m.configRWL.RLock()
+	strict := m.strict
+	m.configRWL.RUnlock()
+	if strict && !knownSignal(s) {
+		return &SignalError{Signal: s, Err: ErrUnknownSignal}
+	}
+	return nil
+}
+
+// checkSignal returns false and logs MsgInvalidSignal if strict validation
+// is enabled and the signal is rejected.
+func (m *Manager) checkSignal(s os.Signal) bool {
+	m.configRWL.RLock()
+	strict := m.strict
+	m.configRWL.RUnlock()
+	if !strict {
+		return true
+	}
+	if err := m.validateSignal(s); err != nil {
+		m.log(m.context(), LevelError, MsgInvalidSignal, KeySignal, s, KeyError, err)
+		return false
+	}
+	return true
+}
+
+// validateSignal returns an error if the signal cannot be trapped.
+func validateSignal(s os.Signal) error {
+	if s == nil || untrappableSignals[s] {
+		return &SignalError{Signal: s, Err: ErrUntrappableSignal}
+	}
+	return nil
+}
+
+// knownSignal returns true if the signal is available on the operating
+// system.
+func knownSignal(s os.Signal) bool {
+	for _, v := range signalNames {
+		if v == s {
+			return true
+		}
+	}
+	_, ok := realtimeSignalName(s)
+	return ok
+}
+
+// SetStrict sets whether the package-level functions validate signals
+// strictly. Please see the Manager's SetStrict function.
+func SetStrict(enabled bool) {
+	defaultManager.SetStrict(enabled)
+}
+
+// WithStrict returns an Option that sets whether signals are validated
+// strictly. Please see the SetStrict function.
+func WithStrict(enabled bool) Option {
+	return func(m *Manager) {
+		m.SetStrict(enabled)
+	}
+}
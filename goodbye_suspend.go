@@ -0,0 +1,91 @@
+// +build go1.8
+
+package goodbye
+
+import "os"
+
+var (
+	// suspendSignal is the signal sent to a process when the user suspends
+	// it from the terminal, ex. Ctrl-Z. It is nil if the operating system
+	// does not support job control.
+	suspendSignal os.Signal
+
+	// resumeSignal is the signal sent to a process when it is continued
+	// after being suspended. It is nil if the operating system does not
+	// support job control.
+	resumeSignal os.Signal
+)
+
+// OnSuspend registers a function to be invoked when this process is
+// suspended from the terminal with SIGTSTP, ex. Ctrl-Z. This allows an
+// interactive program to restore the terminal's state before it is
+// suspended. Once the suspend observers have been invoked the process is
+// stopped.
+//
+// The priority determines when the function is invoked relative to the
+// other suspend observers. Please see RegisterWithPriority for a
+// description of the priority.
+//
+// OnSuspend does nothing on operating systems that do not support job
+// control.
+//
+// The returned function removes the observer when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) OnSuspend(f ObserverFunc, priority int) func() {
+	if suspendSignal == nil {
+		return func() {}
+	}
+	return m.addObserver(&observer{
+		f: f, sig: suspendSignal, priority: priority, suspend: true,
+	})
+}
+
+// OnResume registers a function to be invoked when this process is
+// continued with SIGCONT after being suspended. This allows an interactive
+// program to restore the terminal's state that it saved when it was
+// suspended.
+//
+// The priority determines when the function is invoked relative to the
+// other resume observers. Please see RegisterWithPriority for a
+// description of the priority.
+//
+// OnResume does nothing on operating systems that do not support job
+// control.
+//
+// The returned function removes the observer when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) OnResume(f ObserverFunc, priority int) func() {
+	if resumeSignal == nil {
+		return func() {}
+	}
+	return m.ObserveWithPriority(resumeSignal, f, priority)
+}
+
+// suspend stops the process if any of the observers of the signal were
+// registered with the OnSuspend function. Trapping SIGTSTP prevents the
+// process from being stopped, so the process stops itself once the
+// observers have been invoked.
+func (m *Manager) suspend(s os.Signal, a []*observer) {
+	for _, o := range a {
+		if !o.suspend {
+			continue
+		}
+		if err := stop(); err != nil {
+			m.log(m.context(), LevelError, MsgSuspendFailed, KeySignal, s, KeyError, err)
+		}
+		return
+	}
+}
+
+// OnSuspend registers a function to be invoked when this process is
+// suspended from the terminal. Please see the Manager's OnSuspend function.
+func OnSuspend(f ObserverFunc, priority int) func() {
+	return defaultManager.OnSuspend(f, priority)
+}
+
+// OnResume registers a function to be invoked when this process is
+// continued after being suspended. Please see the Manager's OnResume
+// function.
+func OnResume(f ObserverFunc, priority int) func() {
+	return defaultManager.OnResume(f, priority)
+}
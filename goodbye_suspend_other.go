@@ -0,0 +1,9 @@
+// +build windows js plan9 wasip1
+
+package goodbye
+
+// stop returns ErrNotSupported since the operating system does not support
+// job control.
+func stop() error {
+	return ErrNotSupported
+}
@@ -0,0 +1,19 @@
+// +build !windows,!js,!plan9,!wasip1
+
+package goodbye
+
+import (
+	"os"
+	"syscall"
+)
+
+func init() {
+	suspendSignal = syscall.SIGTSTP
+	resumeSignal = syscall.SIGCONT
+	resizeSignal = syscall.SIGWINCH
+}
+
+// stop stops the process with SIGSTOP, which cannot be trapped.
+func stop() error {
+	return syscall.Kill(os.Getpid(), syscall.SIGSTOP)
+}
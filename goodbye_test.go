@@ -0,0 +1,484 @@
+//go:build go1.20
+// +build go1.20
+
+package goodbye
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestManager returns a Manager whose exiter records the exit codes
+// instead of exiting the process, and whose Logger discards the lifecycle
+// events. The returned function returns the recorded exit codes.
+func newTestManager(t *testing.T) (*Manager, func() []int) {
+	var (
+		lock  sync.Mutex
+		codes []int
+	)
+	m := New()
+	m.SetLogger(LoggerFunc(func(context.Context, Level, string, ...interface{}) {}))
+	m.SetExiter(func(code int) {
+		lock.Lock()
+		defer lock.Unlock()
+		codes = append(codes, code)
+	})
+	t.Cleanup(m.Reset)
+	return m, func() []int {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]int(nil), codes...)
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	errBusy := errors.New("busy")
+	tests := []struct {
+		name string
+
+		// prepare returns the preparer invoked by the first attempt to
+		// shut down. It is given the Manager so that it may abort the
+		// shutdown.
+		prepare func(m *Manager) PrepareFunc
+		want    error
+	}{
+		{
+			name: "veto",
+			prepare: func(*Manager) PrepareFunc {
+				return func(context.Context, os.Signal) error { return errBusy }
+			},
+			want: errBusy,
+		},
+		{
+			name: "abort",
+			prepare: func(m *Manager) PrepareFunc {
+				return func(ctx context.Context, _ os.Signal) error {
+					if !m.Abort() {
+						t.Error("Abort = false, want true")
+					}
+					<-ctx.Done()
+					return nil
+				}
+			},
+			want: ErrAborted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, codes := newTestManager(t)
+			if err := m.Notify(context.Background(), os.Interrupt, 3); err != nil {
+				t.Fatal(err)
+			}
+			var vetoes []error
+			m.SetFeedback(func(_ context.Context, fb Feedback) {
+				if fb.Event == FeedbackVetoed {
+					vetoes = append(vetoes, fb.Err)
+				}
+			})
+			var handled int
+			m.RegisterFunc(func(context.Context, os.Signal) error {
+				handled++
+				return nil
+			})
+
+			var attempts int
+			first := tt.prepare(m)
+			m.RegisterPreparer("test", func(ctx context.Context, s os.Signal) error {
+				if attempts++; attempts == 1 {
+					return first(ctx, s)
+				}
+				return nil
+			})
+
+			m.Trigger(os.Interrupt)
+			if len(vetoes) != 1 || !errors.Is(vetoes[0], tt.want) {
+				t.Fatalf("vetoes = %v, want [%v]", vetoes, tt.want)
+			}
+			if c := codes(); handled != 0 || len(c) != 0 {
+				t.Fatalf("handled = %d, exit codes = %v, want neither", handled, c)
+			}
+			if m.Abort() {
+				t.Fatal("Abort = true, want false without a prepare phase")
+			}
+
+			// The Manager remains armed after a vetoed shutdown.
+			m.Trigger(os.Interrupt)
+			if handled != 1 {
+				t.Fatalf("handled = %d, want 1", handled)
+			}
+			if c := codes(); !reflect.DeepEqual(c, []int{3}) {
+				t.Fatalf("exit codes = %v, want [3]", c)
+			}
+		})
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	errHandler := errors.New("handler failed")
+	tests := []struct {
+		name        string
+		gracePeriod time.Duration
+		handler     ExitFunc
+		want        error
+	}{
+		{
+			name:    "success",
+			handler: func(context.Context, os.Signal) error { return nil },
+		},
+		{
+			name:    "handler error",
+			handler: func(context.Context, os.Signal) error { return errHandler },
+			want:    errHandler,
+		},
+		{
+			name:        "grace period expired",
+			gracePeriod: 100 * time.Millisecond,
+			handler: func(context.Context, os.Signal) error {
+				time.Sleep(500 * time.Millisecond)
+				return nil
+			},
+			want: ErrTimeout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, codes := newTestManager(t)
+			m.SetGracePeriod(tt.gracePeriod)
+			m.SetGracePeriodStackDump(nil)
+			m.RegisterFunc(tt.handler)
+
+			err := m.Shutdown(context.Background())
+			if (tt.want == nil) != (err == nil) || !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if c := codes(); len(c) != 0 {
+				t.Fatalf("exit codes = %v, want none", c)
+			}
+			if err := m.Shutdown(context.Background()); err != ErrShutdownStarted {
+				t.Fatalf("second err = %v, want %v", err, ErrShutdownStarted)
+			}
+		})
+	}
+}
+
+func TestShutdownReset(t *testing.T) {
+	m, codes := newTestManager(t)
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	m.Reset()
+
+	var called bool
+	m.RegisterFunc(func(context.Context, os.Signal) error {
+		called = true
+		return nil
+	})
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Fatal("handler registered after Reset was not executed")
+	}
+	if c := codes(); len(c) != 0 {
+		t.Fatalf("exit codes = %v, want none", c)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	m, codes := newTestManager(t)
+	var dump bytes.Buffer
+	m.SetGracePeriod(100 * time.Millisecond)
+	m.SetGracePeriodStackDump(&dump)
+	m.SetHangTimeout(200 * time.Millisecond)
+
+	release := make(chan struct{})
+	defer close(release)
+	m.RegisterFunc(func(context.Context, os.Signal) error {
+		<-release
+		return nil
+	})
+	if err := m.Shutdown(context.Background()); err != ErrTimeout {
+		t.Fatalf("err = %v, want %v", err, ErrTimeout)
+	}
+
+	// The Manager may be reset and armed again while the handler is still
+	// running.
+	done := make(chan error, 1)
+	go func() {
+		m.Reset()
+		done <- m.Notify(context.Background(), os.Interrupt)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Reset and Notify blocked after a timed out Shutdown")
+	}
+
+	// The watchdog would abort the test process once the hang timeout
+	// expires.
+	time.Sleep(300 * time.Millisecond)
+	if dump.Len() != 0 {
+		t.Fatalf("stack dump written:\n%s", dump.String())
+	}
+	if c := codes(); len(c) != 0 {
+		t.Fatalf("exit codes = %v, want none", c)
+	}
+}
+
+func TestWaitWorkDeadline(t *testing.T) {
+	m, _ := newTestManager(t)
+	m.Add(1)
+	defer m.WorkDone()
+	var closed bool
+	m.RegisterPhaseFunc(PhaseClose, func(context.Context, os.Signal) error {
+		closed = true
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !closed {
+		t.Fatal("PhaseClose handler was not executed")
+	}
+}
+
+func TestInhibitDeadline(t *testing.T) {
+	m, _ := newTestManager(t)
+	defer m.Inhibit("test")()
+	var handled bool
+	m.RegisterFunc(func(context.Context, os.Signal) error {
+		handled = true
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !handled {
+		t.Fatal("handler was not executed")
+	}
+}
+
+func TestPreStopDeadline(t *testing.T) {
+	m, _ := newTestManager(t)
+	m.SetPreStopDelay(time.Minute)
+	var handled bool
+	m.RegisterFunc(func(context.Context, os.Signal) error {
+		handled = true
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !handled {
+		t.Fatal("handler was not executed")
+	}
+}
+
+func TestPriorityTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority int
+		critical bool
+		timeout  func(m *Manager, d time.Duration)
+	}{
+		{
+			name: "priority without critical handler",
+			timeout: func(m *Manager, d time.Duration) {
+				m.SetPriorityTimeout(0, d)
+			},
+		},
+		{
+			name:     "priority with critical handler",
+			critical: true,
+			timeout: func(m *Manager, d time.Duration) {
+				m.SetPriorityTimeout(0, d)
+			},
+		},
+		{
+			name:     "phase without critical handler",
+			priority: PhaseDrain.Priority,
+			timeout: func(m *Manager, d time.Duration) {
+				m.SetPhaseTimeout(PhaseDrain, d)
+			},
+		},
+		{
+			name:     "phase with critical handler",
+			priority: PhaseDrain.Priority,
+			critical: true,
+			timeout: func(m *Manager, d time.Duration) {
+				m.SetPhaseTimeout(PhaseDrain, d)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager(t)
+			if tt.critical {
+				m.RegisterCritical("critical", func(context.Context, os.Signal) error {
+					return nil
+				})
+			}
+			deadline := make(chan bool, 1)
+			m.RegisterNamedFunc("slow", func(ctx context.Context, _ os.Signal) error {
+				_, ok := ctx.Deadline()
+				deadline <- ok
+				time.Sleep(500 * time.Millisecond)
+				return nil
+			}, tt.priority)
+			tt.timeout(m, 100*time.Millisecond)
+
+			start := time.Now()
+			err := m.Shutdown(context.Background())
+			if !errors.Is(err, ErrTimeout) {
+				t.Fatalf("err = %v, want %v", err, ErrTimeout)
+			}
+			if d := time.Since(start); d >= 400*time.Millisecond {
+				t.Fatalf("shutdown took %s, want < 400ms", d)
+			}
+			if !<-deadline {
+				t.Fatal("handler context has no deadline")
+			}
+		})
+	}
+}
+
+func TestCriticalOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		critical bool
+		want     []string
+	}{
+		{"without critical handler", false, []string{"before all", "handler"}},
+		{"with critical handler", true, []string{"critical", "before all", "handler"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager(t)
+			var got []string
+			if tt.critical {
+				m.RegisterCritical("critical", func(context.Context, os.Signal) error {
+					got = append(got, "critical")
+					return nil
+				})
+			}
+			m.BeforeAll(func(context.Context, os.Signal) {
+				got = append(got, "before all")
+			})
+			m.RegisterPhaseFunc(PhaseDrain, func(context.Context, os.Signal) error {
+				got = append(got, "handler")
+				return nil
+			})
+
+			if err := m.Shutdown(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("order = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTriggerDispatch(t *testing.T) {
+	m, codes := newTestManager(t)
+	if err := m.Notify(context.Background(), os.Interrupt, 3); err != nil {
+		t.Fatal(err)
+	}
+	var observed int
+	m.Observe(os.Interrupt, func(context.Context, os.Signal) {
+		observed++
+	})
+	m.SetCoalesceWindow(time.Minute)
+
+	unmask := m.Mask(os.Interrupt)
+	m.Trigger(os.Interrupt)
+	m.Trigger(os.Interrupt)
+	if observed != 1 {
+		t.Fatalf("observed = %d, want 1 with the repeated signal coalesced", observed)
+	}
+	if c := codes(); len(c) != 0 {
+		t.Fatalf("exit codes = %v, want none while masked", c)
+	}
+
+	unmask()
+	deadline := time.Now().Add(time.Second)
+	for len(codes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c := codes(); !reflect.DeepEqual(c, []int{3}) {
+		t.Fatalf("exit codes = %v, want [3] once unmasked", c)
+	}
+}
+
+func TestNotifyInvalidSignal(t *testing.T) {
+	m, _ := newTestManager(t)
+	for _, v := range []interface{}{nil, 1.5, []string{"SIGTERM"}} {
+		err := m.Notify(context.Background(), v)
+		var se *SignalError
+		if !errors.As(err, &se) || se.Err != ErrInvalidSignal {
+			t.Errorf("Notify(%#v) = %v, want %v", v, err, ErrInvalidSignal)
+		}
+	}
+}
+
+func TestParseSignalNumber(t *testing.T) {
+	for _, name := range []string{"0", "-1", "999"} {
+		s, err := ParseSignal(name)
+		if s != nil || !errors.Is(err, ErrUnknownSignal) {
+			t.Errorf("ParseSignal(%q) = %v, %v, want %v", name, s, err, ErrUnknownSignal)
+		}
+		var se *SignalError
+		if !errors.As(err, &se) {
+			t.Errorf("ParseSignal(%q) err = %T, want *SignalError", name, err)
+		}
+	}
+}
+
+func TestEnvErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantSig bool
+	}{
+		{"unknown signal", EnvSignals, "SIGBOGUS", true},
+		{"invalid grace period", EnvGracePeriod, "soon", false},
+		{"invalid exit code", EnvExitCode, "one", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			m := New()
+			m.SetUseEnv(true)
+			err := m.Notify(context.Background())
+			if err == nil {
+				t.Fatal("err = nil, want an error")
+			}
+			var se *SignalError
+			if ok := errors.As(err, &se); ok != tt.wantSig {
+				t.Fatalf("errors.As(%v, *SignalError) = %t, want %t", err, ok, tt.wantSig)
+			}
+			if tt.wantSig && !errors.Is(err, ErrUnknownSignal) {
+				t.Fatalf("err = %v, want %v", err, ErrUnknownSignal)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// +build go1.8
+
+package goodbye
+
+import "os"
+
+// Trigger simulates the receipt of a signal. The signal is dispatched to
+// the observers of the signal and, if the signal is trapped as a result of
+// the Notify function, the exit handlers are executed and the process
+// exits just as if the signal had been received from the operating system.
+//
+// As with a received signal, the signal is discarded if it is coalesced
+// with a previous delivery of the same signal, and queued if it is masked.
+// Please see the SetCoalesceWindow and Mask functions.
+//
+// Unlike a received signal, Trigger does not return until the observers
+// and exit handlers have completed, unless the signal is queued. This
+// allows tests to exercise the exit behavior without sending signals to
+// the test process.
+func (m *Manager) Trigger(sig os.Signal) {
+	if !m.coalesce(sig, true) {
+		m.notifyObservers(sig)
+	}
+
+	m.configRWL.RLock()
+	trapped := m.trapped
+	m.configRWL.RUnlock()
+
+	if m.coalesce(sig, false) || m.mask(sig, trapped) {
+		return
+	}
+	m.dispatch(m.context(), sig, trapped)
+}
+
+// Trigger simulates the receipt of a signal. The signal is dispatched to
+// the observers of the signal and, if the signal is trapped as a result of
+// the Notify function, the exit handlers are executed and the process
+// exits just as if the signal had been received from the operating system.
+//
+// As with a received signal, the signal is discarded if it is coalesced
+// with a previous delivery of the same signal, and queued if it is masked.
+// Please see the SetCoalesceWindow and Mask functions.
+//
+// Unlike a received signal, Trigger does not return until the observers
+// and exit handlers have completed, unless the signal is queued. This
+// allows tests to exercise the exit behavior without sending signals to
+// the test process.
+func Trigger(sig os.Signal) {
+	defaultManager.Trigger(sig)
+}
@@ -1,4 +1,4 @@
-// +build !windows
+// +build !windows,!js,!plan9,!wasip1
 
 package goodbye
 
@@ -9,10 +9,44 @@ import (
 
 func init() {
 	defaultSignals = map[os.Signal]int{
-		syscall.SIGKILL: 1,
 		syscall.SIGHUP:  0,
 		syscall.SIGINT:  0,
 		syscall.SIGQUIT: 0,
 		syscall.SIGTERM: 0,
 	}
+	untrappableSignals = map[os.Signal]bool{
+		syscall.SIGKILL: true,
+		syscall.SIGSTOP: true,
+	}
+	signalNames = map[string]os.Signal{
+		"SIGABRT":   syscall.SIGABRT,
+		"SIGALRM":   syscall.SIGALRM,
+		"SIGBUS":    syscall.SIGBUS,
+		"SIGCHLD":   syscall.SIGCHLD,
+		"SIGCONT":   syscall.SIGCONT,
+		"SIGFPE":    syscall.SIGFPE,
+		"SIGHUP":    syscall.SIGHUP,
+		"SIGILL":    syscall.SIGILL,
+		"SIGINT":    syscall.SIGINT,
+		"SIGKILL":   syscall.SIGKILL,
+		"SIGPIPE":   syscall.SIGPIPE,
+		"SIGPROF":   syscall.SIGPROF,
+		"SIGQUIT":   syscall.SIGQUIT,
+		"SIGSEGV":   syscall.SIGSEGV,
+		"SIGSTOP":   syscall.SIGSTOP,
+		"SIGSYS":    syscall.SIGSYS,
+		"SIGTERM":   syscall.SIGTERM,
+		"SIGTRAP":   syscall.SIGTRAP,
+		"SIGTSTP":   syscall.SIGTSTP,
+		"SIGTTIN":   syscall.SIGTTIN,
+		"SIGTTOU":   syscall.SIGTTOU,
+		"SIGURG":    syscall.SIGURG,
+		"SIGUSR1":   syscall.SIGUSR1,
+		"SIGUSR2":   syscall.SIGUSR2,
+		"SIGVTALRM": syscall.SIGVTALRM,
+		"SIGWINCH":  syscall.SIGWINCH,
+		"SIGXCPU":   syscall.SIGXCPU,
+		"SIGXFSZ":   syscall.SIGXFSZ,
+	}
+	quitSignal = syscall.SIGQUIT
 }
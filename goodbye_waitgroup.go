@@ -0,0 +1,57 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// RegisterWaitGroup registers an exit handler that waits for the wait group
+// to finish. If the wait group does not finish before the timeout expires
+// then the handler returns ErrTimeout, which is recorded in the
+// ShutdownReport, instead of blocking the shutdown. A timeout of zero means
+// the wait is bounded only by the grace period. The handler is named
+// "wait group" and is given a priority of 0.
+//
+// The returned function removes the handler when invoked. It is safe to
+// invoke the returned function more than once.
+func (m *Manager) RegisterWaitGroup(wg *sync.WaitGroup, timeout time.Duration) func() {
+	return m.RegisterNamedFunc("wait group", func(ctx context.Context, s os.Signal) error {
+		return waitGroup(ctx, wg, timeout)
+	}, 0)
+}
+
+// waitGroup waits for the wait group to finish, the timeout to expire, or
+// the context to be done.
+func waitGroup(ctx context.Context, wg *sync.WaitGroup, timeout time.Duration) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-expired:
+		return ErrTimeout
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RegisterWaitGroup registers an exit handler that waits for the wait group
+// to finish. Please see the Manager's RegisterWaitGroup function.
+func RegisterWaitGroup(wg *sync.WaitGroup, timeout time.Duration) func() {
+	return defaultManager.RegisterWaitGroup(wg, timeout)
+}
@@ -0,0 +1,33 @@
+// +build wasip1
+
+package goodbye
+
+import (
+	"os"
+	"syscall"
+)
+
+// WASI does not deliver signals to the process, so the signals are only
+// received from the Trigger function.
+func init() {
+	defaultSignals = map[os.Signal]int{
+		syscall.SIGHUP:  0,
+		syscall.SIGINT:  0,
+		syscall.SIGQUIT: 0,
+		syscall.SIGTERM: 0,
+	}
+	untrappableSignals = map[os.Signal]bool{
+		syscall.SIGKILL: true,
+		syscall.SIGSTOP: true,
+	}
+	signalNames = map[string]os.Signal{
+		"SIGHUP":  syscall.SIGHUP,
+		"SIGINT":  syscall.SIGINT,
+		"SIGKILL": syscall.SIGKILL,
+		"SIGQUIT": syscall.SIGQUIT,
+		"SIGSTOP": syscall.SIGSTOP,
+		"SIGTERM": syscall.SIGTERM,
+		"SIGUSR1": syscall.SIGUSR1,
+		"SIGUSR2": syscall.SIGUSR2,
+	}
+}
@@ -0,0 +1,64 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+)
+
+// SetHangTimeout sets the amount of time, measured from the start of a
+// shutdown, after which the process is aborted if it has still not exited.
+// This is a last resort for when even the forced exit that follows the
+// grace period stalls, ex. because a report writer or an OnComplete
+// function blocks. On operating systems that support it the process raises
+// SIGABRT so the Go runtime writes a traceback of all goroutines before it
+// exits, and otherwise the process panics with the same effect.
+//
+// The hang timeout should be longer than the grace period. A value of zero,
+// the default, disables the watchdog.
+func (m *Manager) SetHangTimeout(d time.Duration) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.hangTimeout = d
+}
+
+// watchdog starts the timer that aborts the process if it has not exited
+// once the hang timeout expires. The returned function stops the timer.
+func (m *Manager) watchdog(ctx context.Context) func() {
+	m.configRWL.RLock()
+	d := m.hangTimeout
+	m.configRWL.RUnlock()
+	if d <= 0 {
+		return func() {}
+	}
+	t := time.AfterFunc(d, func() {
+		m.log(ctx, LevelError, MsgShutdownHung, KeyDuration, d)
+		abort()
+	})
+	return func() { t.Stop() }
+}
+
+// SetHangTimeout sets the amount of time after which the process is
+// aborted if a shutdown has still not exited. Please see the Manager's
+// SetHangTimeout function.
+func SetHangTimeout(d time.Duration) {
+	defaultManager.SetHangTimeout(d)
+}
+
+// WithHangTimeout returns an Option that sets the amount of time after
+// which the process is aborted if a shutdown has still not exited. Please
+// see the SetHangTimeout function.
+func WithHangTimeout(d time.Duration) Option {
+	return func(m *Manager) {
+		m.SetHangTimeout(d)
+	}
+}
+
+// panicAbort panics with a traceback of all goroutines. It is invoked from
+// the watchdog's goroutine, so the panic cannot be recovered.
+func panicAbort() {
+	debug.SetTraceback("all")
+	panic("goodbye: shutdown hung")
+}
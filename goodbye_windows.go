@@ -9,10 +9,35 @@ import (
 
 func init() {
 	defaultSignals = map[os.Signal]int{
-		syscall.SIGKILL: 1,
 		syscall.SIGHUP:  0,
 		os.Interrupt:    0,
 		syscall.SIGQUIT: 0,
 		syscall.SIGTERM: 0,
+
+		CtrlCloseEvent:    0,
+		CtrlLogoffEvent:   0,
+		CtrlShutdownEvent: 0,
+	}
+	untrappableSignals = map[os.Signal]bool{
+		syscall.SIGKILL: true,
+	}
+	signalNames = map[string]os.Signal{
+		"SIGABRT": syscall.SIGABRT,
+		"SIGALRM": syscall.SIGALRM,
+		"SIGBUS":  syscall.SIGBUS,
+		"SIGFPE":  syscall.SIGFPE,
+		"SIGHUP":  syscall.SIGHUP,
+		"SIGILL":  syscall.SIGILL,
+		"SIGINT":  syscall.SIGINT,
+		"SIGKILL": syscall.SIGKILL,
+		"SIGPIPE": syscall.SIGPIPE,
+		"SIGQUIT": syscall.SIGQUIT,
+		"SIGSEGV": syscall.SIGSEGV,
+		"SIGTERM": syscall.SIGTERM,
+		"SIGTRAP": syscall.SIGTRAP,
+
+		"CTRL_CLOSE_EVENT":    CtrlCloseEvent,
+		"CTRL_LOGOFF_EVENT":   CtrlLogoffEvent,
+		"CTRL_SHUTDOWN_EVENT": CtrlShutdownEvent,
 	}
 }
@@ -0,0 +1,122 @@
+// +build go1.8
+
+package goodbye
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// work tracks the amount of in-flight work.
+type work struct {
+	n    int
+	zero chan struct{}
+	sync.Mutex
+}
+
+func (w *work) add(delta int) {
+	w.Lock()
+	defer w.Unlock()
+	if w.zero == nil {
+		w.zero = make(chan struct{})
+		close(w.zero)
+	}
+	if w.n == 0 && delta > 0 {
+		w.zero = make(chan struct{})
+	}
+	w.n += delta
+	if w.n < 0 {
+		panic("goodbye: negative work counter")
+	}
+	if w.n == 0 && delta < 0 {
+		close(w.zero)
+	}
+}
+
+// count returns the amount of in-flight work and a channel that is closed
+// when it reaches zero.
+func (w *work) count() (int, <-chan struct{}) {
+	w.Lock()
+	defer w.Unlock()
+	return w.n, w.zero
+}
+
+// Add adds delta, which may be negative, to the amount of in-flight work.
+// When the process begins exiting, the exit handlers that stop accepting
+// new work, i.e. those with a priority at or below that of PhaseDrain, are
+// executed and then the Manager waits for the in-flight work to complete
+// before executing the remaining handlers. Add panics if the amount of
+// in-flight work becomes negative.
+//
+// The name Done is used by the channel that is closed when the process
+// begins exiting, so work is marked as complete with WorkDone.
+func (m *Manager) Add(delta int) {
+	m.work.add(delta)
+}
+
+// WorkDone decrements the amount of in-flight work by one.
+func (m *Manager) WorkDone() {
+	m.work.add(-1)
+}
+
+// SetWorkTimeout sets the maximum amount of time the Manager waits for the
+// in-flight work to complete during a shutdown. A value of zero, the
+// default, means the wait is bounded only by the grace period. The wait
+// also ends if the context provided to the exit handlers is done.
+func (m *Manager) SetWorkTimeout(d time.Duration) {
+	m.configRWL.Lock()
+	defer m.configRWL.Unlock()
+	m.workTimeout = d
+}
+
+// waitWork blocks until the in-flight work completes, the work timeout
+// expires, or the context is done. The context is the one provided to the
+// exit handlers, which expires with the grace period.
+func (m *Manager) waitWork(ctx context.Context) {
+	n, zero := m.work.count()
+	if n == 0 {
+		return
+	}
+
+	m.configRWL.RLock()
+	d := m.workTimeout
+	m.configRWL.RUnlock()
+
+	var timeout <-chan time.Time
+	if d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	start := time.Now()
+	m.log(ctx, LevelInfo, MsgWorkDraining, KeyInFlight, n)
+	select {
+	case <-zero:
+		m.log(ctx, LevelInfo, MsgWorkDrained, KeyDuration, time.Since(start))
+		return
+	case <-timeout:
+	case <-ctx.Done():
+	}
+	n, _ = m.work.count()
+	m.log(ctx, LevelWarn, MsgWorkDrainTimedOut,
+		KeyInFlight, n, KeyDuration, time.Since(start))
+}
+
+// Add adds delta, which may be negative, to the amount of in-flight work.
+// Please see the Manager's Add function.
+func Add(delta int) {
+	defaultManager.Add(delta)
+}
+
+// WorkDone decrements the amount of in-flight work by one.
+func WorkDone() {
+	defaultManager.WorkDone()
+}
+
+// SetWorkTimeout sets the maximum amount of time the package-level
+// functions wait for the in-flight work to complete during a shutdown.
+func SetWorkTimeout(d time.Duration) {
+	defaultManager.SetWorkTimeout(d)
+}
@@ -0,0 +1,303 @@
+// +build go1.8
+
+/*
+Package goodbyetest provides a fake implementation of the goodbye.Registrar
+interface for testing code that registers exit handlers.
+
+The fake Manager records the registered handlers, executes them when a
+synthetic signal is fired, and records the order in which they were
+executed. It does not trap signals, start goroutines, or exit the process.
+Time is simulated with a fake clock that handlers may advance to exercise
+the grace period.
+*/
+package goodbyetest
+
+import (
+	"context"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// Call is a record of an exit handler that was executed.
+type Call struct {
+	// Name is the name of the handler. Handlers registered without a
+	// name have an empty name.
+	Name string
+
+	// Priority is the priority of the handler.
+	Priority int
+
+	// Signal is the signal provided to the handler.
+	Signal os.Signal
+
+	// Err is the error returned by the handler.
+	Err error
+}
+
+type handler struct {
+	f        goodbye.ExitFunc
+	name     string
+	priority int
+	seq      int
+}
+
+// Manager is a fake implementation of the goodbye.Registrar interface.
+// The zero value is ready for use, although the New function should be
+// used to create a Manager with a fake clock that starts at the current
+// time.
+type Manager struct {
+	handlers    []*handler
+	seq         int
+	calls       []Call
+	now         time.Time
+	gracePeriod time.Duration
+	exited      bool
+	exitCode    int
+
+	// errorExitCode is the exit code used when one or more exit handlers
+	// return an error. errorExitCodeSet is false until SetErrorExitCode
+	// is invoked, in which case the default of 1 is used.
+	errorExitCode    int
+	errorExitCodeSet bool
+
+	// hctx is the context provided to the exit handlers while Fire is
+	// executing them.
+	hctx *deadlineContext
+}
+
+var _ goodbye.Registrar = (*Manager)(nil)
+
+// New returns a new fake Manager.
+func New() *Manager {
+	return &Manager{now: time.Now()}
+}
+
+// Register registers an exit handler with a priority of 0.
+func (m *Manager) Register(f goodbye.ExitHandler) func() {
+	return m.RegisterWithPriority(f, 0)
+}
+
+// RegisterWithPriority registers an exit handler with a priority.
+func (m *Manager) RegisterWithPriority(f goodbye.ExitHandler, priority int) func() {
+	return m.RegisterNamed("", f, priority)
+}
+
+// RegisterFunc registers an exit handler that returns an error with a
+// priority of 0.
+func (m *Manager) RegisterFunc(f goodbye.ExitFunc) func() {
+	return m.RegisterFuncWithPriority(f, 0)
+}
+
+// RegisterFuncWithPriority registers an exit handler that returns an error
+// with a priority.
+func (m *Manager) RegisterFuncWithPriority(f goodbye.ExitFunc, priority int) func() {
+	return m.RegisterNamedFunc("", f, priority)
+}
+
+// RegisterNamed registers a named exit handler with a priority.
+func (m *Manager) RegisterNamed(name string, f goodbye.ExitHandler, priority int) func() {
+	return m.RegisterNamedFunc(name, func(ctx context.Context, s os.Signal) error {
+		f(ctx, s)
+		return nil
+	}, priority)
+}
+
+// RegisterNamedFunc registers a named exit handler that returns an error
+// with a priority.
+func (m *Manager) RegisterNamedFunc(name string, f goodbye.ExitFunc, priority int) func() {
+	m.seq++
+	h := &handler{f: f, name: name, priority: priority, seq: m.seq}
+	m.handlers = append(m.handlers, h)
+	return func() {
+		for i := range m.handlers {
+			if m.handlers[i] == h {
+				m.handlers = append(m.handlers[:i:i], m.handlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of registered exit handlers.
+func (m *Manager) Len() int {
+	return len(m.handlers)
+}
+
+// SetGracePeriod sets the amount of fake time the exit handlers are given
+// to complete. Once a handler advances the fake clock past the grace
+// period, the remaining handlers are not executed and the fake process
+// exits with goodbye.DefaultForcedExitCode. A value of zero, the default,
+// means there is no grace period.
+func (m *Manager) SetGracePeriod(d time.Duration) {
+	m.gracePeriod = d
+}
+
+// SetErrorExitCode sets the exit code used when one or more exit handlers
+// return an error and the fake process would otherwise exit with an exit
+// code of zero. The default value is 1. A value of 0 means handler errors
+// do not affect the exit code.
+func (m *Manager) SetErrorExitCode(exitCode int) {
+	m.errorExitCode = exitCode
+	m.errorExitCodeSet = true
+}
+
+// Now returns the current time of the fake clock.
+func (m *Manager) Now() time.Time {
+	return m.now
+}
+
+// Advance moves the fake clock forward. Exit handlers may invoke Advance
+// to simulate the amount of time they take to complete. The handlers'
+// context is done once the fake clock reaches the end of the grace period.
+func (m *Manager) Advance(d time.Duration) {
+	m.now = m.now.Add(d)
+	if m.hctx != nil && !m.now.Before(m.hctx.deadline) {
+		m.hctx.expire()
+	}
+}
+
+// Fire simulates the receipt of a trapped signal. The exit handlers are
+// executed in order before Fire returns, and the fake process is marked
+// as having exited with the specified exit code. As with the goodbye
+// package, the handlers are executed only once; subsequent invocations
+// of Fire and Exit have no effect until Reset is invoked.
+//
+// If there is a grace period then the handlers are provided with a context
+// whose deadline is the end of the grace period on the fake clock. If one
+// or more of the handlers return an error and the exit code is zero then
+// the fake process exits with the exit code set by SetErrorExitCode.
+func (m *Manager) Fire(ctx context.Context, sig os.Signal, exitCode int) {
+	if m.exited {
+		return
+	}
+	m.exited = true
+	m.exitCode = exitCode
+
+	handlers := append([]*handler(nil), m.handlers...)
+	sort.SliceStable(handlers, func(i, j int) bool {
+		return handlers[i].priority < handlers[j].priority
+	})
+
+	deadline := m.now.Add(m.gracePeriod)
+	expired := func() bool {
+		return m.gracePeriod > 0 && !m.now.Before(deadline)
+	}
+	if m.gracePeriod > 0 {
+		m.hctx = newDeadlineContext(ctx, deadline)
+		defer func() {
+			m.hctx.cancel()
+			m.hctx = nil
+		}()
+		ctx = m.hctx
+	}
+
+	var failed bool
+	for _, h := range handlers {
+		if expired() {
+			m.exitCode = goodbye.DefaultForcedExitCode
+			return
+		}
+		err := h.f(ctx, sig)
+		m.calls = append(m.calls, Call{
+			Name:     h.name,
+			Priority: h.priority,
+			Signal:   sig,
+			Err:      err,
+		})
+		failed = failed || err != nil
+	}
+	switch {
+	case expired():
+		m.exitCode = goodbye.DefaultForcedExitCode
+	case failed && m.exitCode == 0:
+		m.exitCode = 1
+		if m.errorExitCodeSet {
+			m.exitCode = m.errorExitCode
+		}
+	}
+}
+
+// deadlineContext is a context whose deadline is measured with the fake
+// clock. It is done when its parent is done or when the fake clock is
+// advanced past its deadline, so it does not start a timer.
+type deadlineContext struct {
+	context.Context
+	cancel   context.CancelFunc
+	deadline time.Time
+	expired  bool
+}
+
+func newDeadlineContext(parent context.Context, deadline time.Time) *deadlineContext {
+	ctx, cancel := context.WithCancel(parent)
+	return &deadlineContext{Context: ctx, cancel: cancel, deadline: deadline}
+}
+
+func (c *deadlineContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *deadlineContext) Err() error {
+	if c.expired {
+		return context.DeadlineExceeded
+	}
+	return c.Context.Err()
+}
+
+// expire marks the context as having exceeded its deadline.
+func (c *deadlineContext) expire() {
+	if c.Context.Err() == nil {
+		c.expired = true
+	}
+	c.cancel()
+}
+
+// Exit simulates the Exit function. The exit handlers are provided with
+// the signal for which goodbye.IsNormalExit returns true.
+func (m *Manager) Exit(ctx context.Context, exitCode int) {
+	m.Fire(ctx, goodbye.NormalExitSignal(), exitCode)
+}
+
+// Calls returns the exit handlers that were executed in the order in which
+// they were executed.
+func (m *Manager) Calls() []Call {
+	return append([]Call(nil), m.calls...)
+}
+
+// Order returns the names of the exit handlers that were executed in the
+// order in which they were executed.
+func (m *Manager) Order() []string {
+	names := make([]string, len(m.calls))
+	for i, c := range m.calls {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Ran returns true if an exit handler with the specified name was
+// executed.
+func (m *Manager) Ran(name string) bool {
+	for _, c := range m.calls {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Exited returns the exit code of the fake process and true if the exit
+// handlers were executed as a result of Fire or Exit.
+func (m *Manager) Exited() (int, bool) {
+	return m.exitCode, m.exited
+}
+
+// Reset removes the registered exit handlers and the record of their
+// execution so that the Manager may be reused.
+func (m *Manager) Reset() {
+	m.handlers = nil
+	m.calls = nil
+	m.exited = false
+	m.exitCode = 0
+}
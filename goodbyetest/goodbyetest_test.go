@@ -0,0 +1,61 @@
+// +build go1.8
+
+package goodbyetest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFireErrorExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      bool
+		code     int
+		exitCode int
+		want     int
+	}{
+		{name: "default", want: 1},
+		{name: "set", set: true, code: 3, want: 3},
+		{name: "disabled", set: true, code: 0, want: 0},
+		{name: "nonzero exit code", exitCode: 2, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			if tt.set {
+				m.SetErrorExitCode(tt.code)
+			}
+			m.RegisterFunc(func(context.Context, os.Signal) error {
+				return errors.New("failed")
+			})
+			m.Fire(context.Background(), os.Interrupt, tt.exitCode)
+			if code, _ := m.Exited(); code != tt.want {
+				t.Fatalf("exit code = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestFireDeadline(t *testing.T) {
+	m := New()
+	m.SetGracePeriod(time.Second)
+	want := m.Now().Add(time.Second)
+	var err error
+	m.RegisterFunc(func(ctx context.Context, _ os.Signal) error {
+		if d, ok := ctx.Deadline(); !ok || !d.Equal(want) {
+			t.Errorf("deadline = %v, %t, want %v", d, ok, want)
+		}
+		m.Advance(time.Second)
+		<-ctx.Done()
+		err = ctx.Err()
+		return nil
+	})
+	m.Fire(context.Background(), os.Interrupt, 0)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("ctx.Err() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Package grpcgrace gracefully stops a gRPC server when the process exits with
+the goodbye package.
+
+	srv := grpc.NewServer()
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+	grpcgrace.Register(srv, 20*time.Second, grpcgrace.WithHealth(hs))
+
+The server is stopped during the goodbye.PhaseDrain phase so that it stops
+accepting RPCs before the resources used by the RPCs are closed.
+*/
+package grpcgrace
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// Option configures Register.
+type Option func(c *config)
+
+type config struct {
+	m        *goodbye.Manager
+	name     string
+	priority int
+	health   *health.Server
+}
+
+// WithManager returns an Option that registers the exit handler with the
+// Manager instead of the default Manager.
+func WithManager(m *goodbye.Manager) Option {
+	return func(c *config) {
+		c.m = m
+	}
+}
+
+// WithName returns an Option that sets the name of the exit handler. The
+// default name is "grpc server".
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithPriority returns an Option that sets the priority of the exit
+// handler. The default value is the priority of goodbye.PhaseDrain.
+func WithPriority(priority int) Option {
+	return func(c *config) {
+		c.priority = priority
+	}
+}
+
+// WithHealth returns an Option that sets the status of all of the services
+// of the health server to NOT_SERVING before the gRPC server is stopped,
+// so that clients and load balancers stop sending new RPCs.
+func WithHealth(hs *health.Server) Option {
+	return func(c *config) {
+		c.health = hs
+	}
+}
+
+// Register registers an exit handler that gracefully stops the server. The
+// handler invokes the server's GracefulStop function, which waits for the
+// pending RPCs to complete, and invokes the server's Stop function if they
+// do not complete before the timeout or the context expires. A timeout of
+// zero means GracefulStop is bounded only by the grace period.
+//
+// The returned function removes the exit handler when invoked.
+func Register(srv *grpc.Server, timeout time.Duration, opts ...Option) func() {
+	c := config{
+		m:        goodbye.Default(),
+		name:     "grpc server",
+		priority: goodbye.PhaseDrain.Priority,
+	}
+	for _, o := range opts {
+		o(&c)
+	}
+	return c.m.RegisterNamedFunc(c.name, func(ctx context.Context, s os.Signal) error {
+		if c.health != nil {
+			c.health.Shutdown()
+		}
+		return Stop(ctx, srv, timeout)
+	}, c.priority)
+}
+
+// Stop gracefully stops the server, waiting for the pending RPCs to
+// complete for at most the timeout before stopping the server forcibly. A
+// timeout of zero means Stop waits until the context is done. The
+// context's error is returned if the server is stopped forcibly.
+func Stop(ctx context.Context, srv *grpc.Server, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		srv.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
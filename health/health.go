@@ -0,0 +1,151 @@
+/*
+Package health reports the readiness and liveness of a process that exits
+with the goodbye package.
+
+The process is ready until it begins exiting, at which point it becomes not
+ready immediately, before the exit handlers are executed, so that load
+balancers stop routing new traffic to it while it drains. The process
+remains live until it exits so that it is not restarted during the drain.
+
+	mux.Handle("/readyz", health.ReadyHandler())
+	mux.Handle("/livez", health.LiveHandler())
+*/
+package health
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// Checker reports the readiness and liveness of a process.
+type Checker struct {
+	m       *goodbye.Manager
+	ready   bool
+	healthy bool
+	sync.RWMutex
+}
+
+// New returns a Checker for the Manager. The Checker is ready and healthy
+// until the Manager begins exiting or SetReady or SetHealthy is invoked.
+func New(m *goodbye.Manager) *Checker {
+	return &Checker{m: m, ready: true, healthy: true}
+}
+
+// SetReady sets whether the process is ready, ex. to report that it is
+// not ready until it has finished starting. The process is never ready
+// once it begins exiting.
+func (c *Checker) SetReady(ready bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.ready = ready
+}
+
+// SetHealthy sets whether the process is healthy.
+func (c *Checker) SetHealthy(healthy bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.healthy = healthy
+}
+
+// Ready returns true if the process is ready to receive traffic. It is
+// false once the Manager begins exiting.
+func (c *Checker) Ready() bool {
+	if c.m.IsShuttingDown() {
+		return false
+	}
+	c.RLock()
+	defer c.RUnlock()
+	return c.ready
+}
+
+// Healthy returns true if the process is live. It is not affected by the
+// Manager exiting.
+func (c *Checker) Healthy() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.healthy
+}
+
+// ReadyHandler returns an http.Handler that responds with 200 OK if the
+// process is ready and 503 Service Unavailable if it is not.
+func (c *Checker) ReadyHandler() http.Handler {
+	return handler(c.Ready)
+}
+
+// LiveHandler returns an http.Handler that responds with 200 OK if the
+// process is healthy and 503 Service Unavailable if it is not.
+func (c *Checker) LiveHandler() http.Handler {
+	return handler(c.Healthy)
+}
+
+// Handler returns an http.Handler that serves the readiness at "/readyz"
+// and the liveness at "/livez" and "/healthz".
+func (c *Checker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/readyz", c.ReadyHandler())
+	mux.Handle("/livez", c.LiveHandler())
+	mux.Handle("/healthz", c.LiveHandler())
+	return mux
+}
+
+func handler(ok func() bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if !ok() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("not ok\n"))
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+}
+
+// defaultChecker is the Checker of the default Manager.
+var defaultChecker = New(goodbye.Default())
+
+// Default returns the Checker of the default Manager.
+func Default() *Checker {
+	return defaultChecker
+}
+
+// Ready returns true if the process is ready to receive traffic. It is
+// false once the default Manager begins exiting.
+func Ready() bool {
+	return defaultChecker.Ready()
+}
+
+// Healthy returns true if the process is live.
+func Healthy() bool {
+	return defaultChecker.Healthy()
+}
+
+// SetReady sets whether the process is ready.
+func SetReady(ready bool) {
+	defaultChecker.SetReady(ready)
+}
+
+// SetHealthy sets whether the process is healthy.
+func SetHealthy(healthy bool) {
+	defaultChecker.SetHealthy(healthy)
+}
+
+// ReadyHandler returns an http.Handler that reports the readiness of the
+// process.
+func ReadyHandler() http.Handler {
+	return defaultChecker.ReadyHandler()
+}
+
+// LiveHandler returns an http.Handler that reports the liveness of the
+// process.
+func LiveHandler() http.Handler {
+	return defaultChecker.LiveHandler()
+}
+
+// Handler returns an http.Handler that serves the readiness and liveness
+// of the process.
+func Handler() http.Handler {
+	return defaultChecker.Handler()
+}
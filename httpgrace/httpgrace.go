@@ -0,0 +1,114 @@
+// +build go1.8
+
+/*
+Package httpgrace gracefully shuts down an http.Server when the process
+exits with the goodbye package.
+
+	srv := &http.Server{Addr: ":8080", Handler: mux}
+	if err := httpgrace.GracefulServe(srv, 20*time.Second); err != nil {
+		log.Fatal(err)
+	}
+
+The server is shut down during the goodbye.PhaseDrain phase so that it stops
+accepting requests before the resources used by the requests are closed.
+*/
+package httpgrace
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// Option configures GracefulServe.
+type Option func(c *config)
+
+type config struct {
+	m        *goodbye.Manager
+	l        net.Listener
+	priority int
+}
+
+// WithManager returns an Option that registers the exit handler with the
+// Manager instead of the default Manager.
+func WithManager(m *goodbye.Manager) Option {
+	return func(c *config) {
+		c.m = m
+	}
+}
+
+// WithListener returns an Option that serves requests accepted by the
+// listener instead of listening on the server's address.
+func WithListener(l net.Listener) Option {
+	return func(c *config) {
+		c.l = l
+	}
+}
+
+// WithPriority returns an Option that sets the priority of the exit
+// handler. The default value is the priority of goodbye.PhaseDrain.
+func WithPriority(priority int) Option {
+	return func(c *config) {
+		c.priority = priority
+	}
+}
+
+// GracefulServe registers an exit handler that shuts down the server and
+// then serves requests until the server is shut down.
+//
+// The exit handler invokes the server's Shutdown function, which waits for
+// active connections to become idle for at most the timeout, and then
+// invokes the server's Close function to close the connections that remain.
+// A timeout of zero means Shutdown is bounded only by the grace period.
+//
+// GracefulServe returns nil when the server is shut down by the exit
+// handler. If the server fails for any other reason then the exit handler
+// is removed, the error is provided to the Manager's ExitErr function so
+// that the process exits, and the error is returned.
+func GracefulServe(srv *http.Server, timeout time.Duration, opts ...Option) error {
+	c := config{m: goodbye.Default(), priority: goodbye.PhaseDrain.Priority}
+	for _, o := range opts {
+		o(&c)
+	}
+
+	name := "http server"
+	if srv.Addr != "" {
+		name += " " + srv.Addr
+	}
+	unregister := c.m.RegisterNamedFunc(name, func(ctx context.Context, s os.Signal) error {
+		return Shutdown(ctx, srv, timeout)
+	}, c.priority)
+
+	var err error
+	if c.l != nil {
+		err = srv.Serve(c.l)
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	unregister()
+	c.m.ExitErr(context.Background(), err)
+	return err
+}
+
+// Shutdown gracefully shuts down the server, waiting for active connections
+// to become idle for at most the timeout before closing them. A timeout of
+// zero means Shutdown waits until the context is done.
+func Shutdown(ctx context.Context, srv *http.Server, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := srv.Shutdown(ctx); err != nil {
+		srv.Close()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+Package k8s fits the shutdown of a process running in a Kubernetes pod into
+the pod's termination grace period.
+
+When a pod is deleted, the kubelet sends SIGTERM to its containers and then
+sends SIGKILL once the pod's terminationGracePeriodSeconds have elapsed. The
+Configure function sets the goodbye package's grace period to the pod's
+grace period minus a safety margin, so that the exit handlers complete, or
+the process exits with the forced exit code, before the kubelet kills it.
+
+The grace period is not available through the Downward API, so it is
+typically provided to the container with an environment variable:
+
+	spec:
+	  terminationGracePeriodSeconds: 60
+	  containers:
+	  - name: app
+	    env:
+	    - name: TERMINATION_GRACE_PERIOD_SECONDS
+	      value: "60"
+
+and the process configures itself at startup:
+
+	if _, err := k8s.Configure(); err != nil {
+		log.Fatal(err)
+	}
+*/
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// EnvGracePeriod is the name of the environment variable from which the
+// pod's termination grace period, in seconds, is read.
+const EnvGracePeriod = "TERMINATION_GRACE_PERIOD_SECONDS"
+
+// DefaultGracePeriod is the termination grace period used by Kubernetes
+// when a pod does not specify one.
+const DefaultGracePeriod = 30 * time.Second
+
+// DefaultMargin is the default amount of time subtracted from the pod's
+// termination grace period.
+const DefaultMargin = 3 * time.Second
+
+// Option configures Configure.
+type Option func(c *config)
+
+type config struct {
+	m           *goodbye.Manager
+	gracePeriod time.Duration
+	margin      time.Duration
+}
+
+// WithManager returns an Option that configures the Manager instead of the
+// default Manager.
+func WithManager(m *goodbye.Manager) Option {
+	return func(c *config) {
+		c.m = m
+	}
+}
+
+// WithGracePeriod returns an Option that sets the pod's termination grace
+// period explicitly instead of reading it from the environment.
+func WithGracePeriod(d time.Duration) Option {
+	return func(c *config) {
+		c.gracePeriod = d
+	}
+}
+
+// WithMargin returns an Option that sets the amount of time subtracted from
+// the pod's termination grace period. The default value is DefaultMargin.
+func WithMargin(d time.Duration) Option {
+	return func(c *config) {
+		c.margin = d
+	}
+}
+
+// GracePeriod returns the pod's termination grace period from the
+// EnvGracePeriod environment variable, or DefaultGracePeriod if it is not
+// set.
+func GracePeriod() (time.Duration, error) {
+	s := os.Getenv(EnvGracePeriod)
+	if s == "" {
+		return DefaultGracePeriod, nil
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("k8s: invalid %s: %s", EnvGracePeriod, s)
+	}
+	return time.Duration(n * float64(time.Second)), nil
+}
+
+// Configure sets the Manager's grace period to the pod's termination grace
+// period minus the margin, and returns the grace period that was set. If
+// the margin is greater than half of the pod's grace period then half of
+// the pod's grace period is used instead, so that the exit handlers are
+// always given time to run.
+func Configure(opts ...Option) (time.Duration, error) {
+	c := config{m: goodbye.Default(), margin: DefaultMargin}
+	for _, o := range opts {
+		o(&c)
+	}
+
+	pod := c.gracePeriod
+	if pod <= 0 {
+		var err error
+		if pod, err = GracePeriod(); err != nil {
+			return 0, err
+		}
+	}
+	if pod <= 0 {
+		return 0, fmt.Errorf("k8s: grace period must be positive: %s", pod)
+	}
+
+	margin := c.margin
+	if margin > pod/2 {
+		margin = pod / 2
+	}
+	d := pod - margin
+	c.m.SetGracePeriod(d)
+	return d, nil
+}
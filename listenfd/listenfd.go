@@ -0,0 +1,230 @@
+/*
+Package listenfd passes listening sockets from one process to another using
+the socket activation protocol of systemd.
+
+A process that is started by systemd with socket activation, or by a process
+that exports its listeners with the Export function, inherits the listeners
+by name:
+
+	set, err := listenfd.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	l, err := set.Listen("http", "tcp", ":8080")
+
+Listen returns the inherited listener with the name if one exists, and
+otherwise creates a new listener. The listeners in the Set may then be
+exported to a new process, ex. when the process restarts:
+
+	cmd := exec.Command(os.Args[0], os.Args[1:]...)
+	if err := set.Export(cmd); err != nil {
+		log.Fatal(err)
+	}
+*/
+package listenfd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The names of the environment variables of the socket activation
+// protocol.
+const (
+	// EnvListenFDs is the number of inherited file descriptors.
+	EnvListenFDs = "LISTEN_FDS"
+
+	// EnvListenPID is the ID of the process that should inherit the file
+	// descriptors.
+	EnvListenPID = "LISTEN_PID"
+
+	// EnvListenFDNames is a colon-separated list of the names of the
+	// inherited file descriptors.
+	EnvListenFDNames = "LISTEN_FDNAMES"
+)
+
+// StartFD is the first inherited file descriptor.
+const StartFD = 3
+
+// filer is implemented by listeners whose file descriptor may be exported,
+// such as *net.TCPListener and *net.UnixListener.
+type filer interface {
+	File() (*os.File, error)
+}
+
+// Inherit returns the listeners inherited by the process, keyed by name,
+// and unsets the environment variables so that the listeners are not passed
+// to child processes. Listeners without a name have the name "unknown". If
+// EnvListenPID is set and is not the ID of the process then no listeners
+// are returned. EnvListenPID may be unset since a parent process that execs
+// a child with the os/exec package does not know the child's ID in advance.
+func Inherit() (map[string][]net.Listener, error) {
+	defer func() {
+		os.Unsetenv(EnvListenFDs)
+		os.Unsetenv(EnvListenPID)
+		os.Unsetenv(EnvListenFDNames)
+	}()
+
+	if p := os.Getenv(EnvListenPID); p != "" {
+		pid, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("listenfd: invalid %s: %s", EnvListenPID, p)
+		}
+		if pid != os.Getpid() {
+			return nil, nil
+		}
+	}
+	s := os.Getenv(EnvListenFDs)
+	if s == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("listenfd: invalid %s: %s", EnvListenFDs, s)
+	}
+
+	var names []string
+	if s := os.Getenv(EnvListenFDNames); s != "" {
+		names = strings.Split(s, ":")
+	}
+
+	listeners := map[string][]net.Listener{}
+	for i := 0; i < n; i++ {
+		name := "unknown"
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		f := os.NewFile(uintptr(StartFD+i), name)
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, a := range listeners {
+				for _, l := range a {
+					l.Close()
+				}
+			}
+			return nil, fmt.Errorf("listenfd: fd %d: %v", StartFD+i, err)
+		}
+		listeners[name] = append(listeners[name], l)
+	}
+	return listeners, nil
+}
+
+// Set is a set of named listeners that may be exported to another process.
+type Set struct {
+	inherited map[string][]net.Listener
+	names     []string
+	listeners []net.Listener
+	sync.Mutex
+}
+
+// New returns a Set that contains the listeners inherited by the process.
+func New() (*Set, error) {
+	inherited, err := Inherit()
+	if err != nil {
+		return nil, err
+	}
+	if inherited == nil {
+		inherited = map[string][]net.Listener{}
+	}
+	return &Set{inherited: inherited}, nil
+}
+
+// Listen returns the inherited listener with the name, or if there is not
+// one, announces on the local network address. The listener is added to
+// the Set.
+func (s *Set) Listen(name, network, address string) (net.Listener, error) {
+	s.Lock()
+	defer s.Unlock()
+	if a := s.inherited[name]; len(a) > 0 {
+		l := a[0]
+		s.inherited[name] = a[1:]
+		s.add(name, l)
+		return l, nil
+	}
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	s.add(name, l)
+	return l, nil
+}
+
+// Add adds a listener to the Set. The listener must implement a File
+// function, as *net.TCPListener and *net.UnixListener do, to be exported.
+func (s *Set) Add(name string, l net.Listener) {
+	s.Lock()
+	defer s.Unlock()
+	s.add(name, l)
+}
+
+func (s *Set) add(name string, l net.Listener) {
+	s.names = append(s.names, name)
+	s.listeners = append(s.listeners, l)
+}
+
+// Export configures cmd to inherit the listeners in the Set. The
+// listeners' file descriptors are added to the beginning of cmd.ExtraFiles
+// and the environment variables of the socket activation protocol are
+// added to cmd.Env. If cmd.Env is nil then the process's environment is
+// used.
+func (s *Set) Export(cmd *exec.Cmd) error {
+	s.Lock()
+	defer s.Unlock()
+
+	files := make([]*os.File, 0, len(s.listeners))
+	for i, l := range s.listeners {
+		fl, ok := l.(filer)
+		if !ok {
+			closeFiles(files)
+			return fmt.Errorf("listenfd: %s: listener cannot be exported", s.names[i])
+		}
+		f, err := fl.File()
+		if err != nil {
+			closeFiles(files)
+			return fmt.Errorf("listenfd: %s: %v", s.names[i], err)
+		}
+		files = append(files, f)
+	}
+
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = append(env,
+		EnvListenFDs+"="+strconv.Itoa(len(files)),
+		EnvListenFDNames+"="+strings.Join(s.names, ":"))
+	cmd.ExtraFiles = append(files, cmd.ExtraFiles...)
+	return nil
+}
+
+// Close closes the listeners in the Set and the inherited listeners that
+// were not used.
+func (s *Set) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	var first error
+	for _, l := range s.listeners {
+		if err := l.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	for _, a := range s.inherited {
+		for _, l := range a {
+			l.Close()
+		}
+	}
+	s.names, s.listeners, s.inherited = nil, nil, map[string][]net.Listener{}
+	return first
+}
+
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
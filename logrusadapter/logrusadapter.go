@@ -0,0 +1,48 @@
+/*
+Package logrusadapter provides a goodbye.Logger that logs with logrus.
+*/
+package logrusadapter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+type logger struct {
+	l logrus.FieldLogger
+}
+
+// New returns a goodbye.Logger that logs with the logrus.FieldLogger, ex.
+// a *logrus.Logger or *logrus.Entry.
+func New(l logrus.FieldLogger) goodbye.Logger {
+	return &logger{l: l}
+}
+
+// Log logs the message with the logrus.FieldLogger. The keyvals are
+// provided to the logrus.FieldLogger as fields.
+func (a *logger) Log(ctx context.Context, level goodbye.Level, msg string, keyvals ...interface{}) {
+	fields := logrus.Fields{}
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 == len(keyvals) {
+			fields["!BADKEY"] = keyvals[i]
+			break
+		}
+		fields[fmt.Sprint(keyvals[i])] = keyvals[i+1]
+	}
+
+	e := a.l.WithFields(fields)
+	switch level {
+	case goodbye.LevelDebug:
+		e.Debug(msg)
+	case goodbye.LevelInfo:
+		e.Info(msg)
+	case goodbye.LevelWarn:
+		e.Warn(msg)
+	default:
+		e.Error(msg)
+	}
+}
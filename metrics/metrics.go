@@ -0,0 +1,199 @@
+/*
+Package metrics provides a Prometheus collector for the lifecycle events of
+the goodbye package.
+
+The Collector implements the goodbye.Logger interface, so it is installed
+with the goodbye.SetLogger function. Log messages may still be written by
+providing another goodbye.Logger to the New function:
+
+	c := metrics.New("myapp", logger)
+	prometheus.MustRegister(c)
+	goodbye.SetLogger(c)
+
+Because the process exits once the exit handlers have completed, metrics
+about the shutdown are typically pushed to a Prometheus Pushgateway by a
+late exit handler or scraped during a drain phase.
+*/
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// Collector is a prometheus.Collector and goodbye.Logger that records the
+// lifecycle events of the goodbye package as metrics.
+type Collector struct {
+	next goodbye.Logger
+
+	signals          *prometheus.CounterVec
+	handlersExecuted *prometheus.CounterVec
+	handlersFailed   *prometheus.CounterVec
+	handlersTimedOut *prometheus.CounterVec
+	handlersSlow     *prometheus.CounterVec
+	handlerDuration  *prometheus.HistogramVec
+	shutdownDuration prometheus.Histogram
+
+	shutdownStart time.Time
+	lock          sync.Mutex
+}
+
+var (
+	_ prometheus.Collector = (*Collector)(nil)
+	_ goodbye.Logger       = (*Collector)(nil)
+)
+
+// New returns a new Collector. The namespace is used as the prefix of the
+// names of the metrics. The messages logged to the Collector are relayed
+// to the next Logger if it is not nil.
+//
+// The Collector records the following metrics:
+//
+//	<namespace>_goodbye_signals_received_total{signal}
+//	<namespace>_goodbye_handlers_executed_total{handler}
+//	<namespace>_goodbye_handlers_failed_total{handler}
+//	<namespace>_goodbye_handlers_timed_out_total{handler}
+//	<namespace>_goodbye_handlers_slow_total{handler}
+//	<namespace>_goodbye_handler_duration_seconds{handler}
+//	<namespace>_goodbye_shutdown_duration_seconds
+func New(namespace string, next goodbye.Logger) *Collector {
+	const subsystem = "goodbye"
+	buckets := prometheus.ExponentialBuckets(0.01, 2, 14)
+	return &Collector{
+		next: next,
+		signals: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "signals_received_total",
+			Help:      "The number of trapped and observed signals received.",
+		}, []string{"signal"}),
+		handlersExecuted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "handlers_executed_total",
+			Help:      "The number of exit handlers that completed.",
+		}, []string{"handler"}),
+		handlersFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "handlers_failed_total",
+			Help:      "The number of exit handlers that returned an error.",
+		}, []string{"handler"}),
+		handlersTimedOut: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "handlers_timed_out_total",
+			Help:      "The number of exit handlers that timed out.",
+		}, []string{"handler"}),
+		handlersSlow: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "handlers_slow_total",
+			Help:      "The number of exit handlers that exceeded the slow handler threshold.",
+		}, []string{"handler"}),
+		handlerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "handler_duration_seconds",
+			Help:      "The amount of time exit handlers took to complete.",
+			Buckets:   buckets,
+		}, []string{"handler"}),
+		shutdownDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "shutdown_duration_seconds",
+			Help:      "The amount of time the shutdown took to complete.",
+			Buckets:   buckets,
+		}),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.signals.Describe(ch)
+	c.handlersExecuted.Describe(ch)
+	c.handlersFailed.Describe(ch)
+	c.handlersTimedOut.Describe(ch)
+	c.handlersSlow.Describe(ch)
+	c.handlerDuration.Describe(ch)
+	c.shutdownDuration.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.signals.Collect(ch)
+	c.handlersExecuted.Collect(ch)
+	c.handlersFailed.Collect(ch)
+	c.handlersTimedOut.Collect(ch)
+	c.handlersSlow.Collect(ch)
+	c.handlerDuration.Collect(ch)
+	c.shutdownDuration.Collect(ch)
+}
+
+// Log implements the goodbye.Logger interface. The message is recorded as
+// metrics and then relayed to the next Logger.
+func (c *Collector) Log(ctx context.Context, level goodbye.Level, msg string, keyvals ...interface{}) {
+	c.record(msg, keyvals)
+	if c.next != nil {
+		c.next.Log(ctx, level, msg, keyvals...)
+	}
+}
+
+func (c *Collector) record(msg string, keyvals []interface{}) {
+	switch msg {
+	case goodbye.MsgSignalReceived, goodbye.MsgSignalObserved:
+		c.signals.WithLabelValues(value(keyvals, goodbye.KeySignal)).Inc()
+	case goodbye.MsgShutdownStarted:
+		c.lock.Lock()
+		c.shutdownStart = time.Now()
+		c.lock.Unlock()
+	case goodbye.MsgHandlerFinished:
+		h := value(keyvals, goodbye.KeyHandler)
+		c.handlersExecuted.WithLabelValues(h).Inc()
+		c.observeDuration(h, keyvals)
+	case goodbye.MsgHandlerFailed:
+		h := value(keyvals, goodbye.KeyHandler)
+		c.handlersExecuted.WithLabelValues(h).Inc()
+		c.handlersFailed.WithLabelValues(h).Inc()
+		c.observeDuration(h, keyvals)
+	case goodbye.MsgHandlerTimedOut:
+		c.handlersTimedOut.WithLabelValues(value(keyvals, goodbye.KeyHandler)).Inc()
+	case goodbye.MsgHandlerSlow:
+		c.handlersSlow.WithLabelValues(value(keyvals, goodbye.KeyHandler)).Inc()
+	case goodbye.MsgExiting:
+		c.lock.Lock()
+		start := c.shutdownStart
+		c.lock.Unlock()
+		if !start.IsZero() {
+			c.shutdownDuration.Observe(time.Since(start).Seconds())
+		}
+	}
+}
+
+func (c *Collector) observeDuration(handler string, keyvals []interface{}) {
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] != goodbye.KeyDuration {
+			continue
+		}
+		if d, ok := keyvals[i+1].(time.Duration); ok {
+			c.handlerDuration.WithLabelValues(handler).Observe(d.Seconds())
+		}
+		return
+	}
+}
+
+// value returns the string form of the value associated with the key.
+func value(keyvals []interface{}, key string) string {
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == key {
+			return fmt.Sprint(keyvals[i+1])
+		}
+	}
+	return ""
+}
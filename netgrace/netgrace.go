@@ -0,0 +1,216 @@
+/*
+Package netgrace provides a net.Listener that drains its connections when
+the process exits with the goodbye package.
+
+	l, err := net.Listen("tcp", ":9000")
+	if err != nil {
+		log.Fatal(err)
+	}
+	l = netgrace.Wrap(l, 20*time.Second)
+
+When the process begins exiting, the listener stops accepting connections
+and waits for the active connections to be closed by their owners. The
+connections that remain open when the timeout expires are closed.
+*/
+package netgrace
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// Option configures a Listener.
+type Option func(c *config)
+
+type config struct {
+	m        *goodbye.Manager
+	name     string
+	priority int
+}
+
+// WithManager returns an Option that registers the exit handler with the
+// Manager instead of the default Manager.
+func WithManager(m *goodbye.Manager) Option {
+	return func(c *config) {
+		c.m = m
+	}
+}
+
+// WithName returns an Option that sets the name of the exit handler. The
+// default name is "listener" followed by the listener's address.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithPriority returns an Option that sets the priority of the exit
+// handler. The default value is the priority of goodbye.PhaseDrain.
+func WithPriority(priority int) Option {
+	return func(c *config) {
+		c.priority = priority
+	}
+}
+
+// Listener is a net.Listener that tracks the connections it accepts so
+// that they may be drained when the process exits.
+type Listener struct {
+	net.Listener
+
+	timeout    time.Duration
+	unregister func()
+
+	conns  map[*conn]struct{}
+	idle   chan struct{}
+	closed bool
+	sync.Mutex
+}
+
+// Wrap returns a Listener that accepts connections from l and registers an
+// exit handler that invokes the Listener's Drain function with the
+// timeout. A timeout of zero means Drain is bounded only by the grace
+// period.
+func Wrap(l net.Listener, timeout time.Duration, opts ...Option) *Listener {
+	c := config{
+		m:        goodbye.Default(),
+		name:     "listener " + l.Addr().String(),
+		priority: goodbye.PhaseDrain.Priority,
+	}
+	for _, o := range opts {
+		o(&c)
+	}
+
+	dl := &Listener{
+		Listener: l,
+		timeout:  timeout,
+		conns:    map[*conn]struct{}{},
+	}
+	dl.unregister = c.m.RegisterNamedFunc(c.name, func(ctx context.Context, s os.Signal) error {
+		return dl.Drain(ctx)
+	}, c.priority)
+	return dl
+}
+
+// Accept waits for and returns the next connection. The connection is
+// tracked until it is closed.
+func (l *Listener) Accept() (net.Conn, error) {
+	nc, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	c := &conn{Conn: nc, l: l}
+
+	l.Lock()
+	defer l.Unlock()
+	if l.closed {
+		nc.Close()
+		return nil, errClosed
+	}
+	l.conns[c] = struct{}{}
+	return c, nil
+}
+
+// Close stops accepting connections and removes the exit handler. Active
+// connections are not closed.
+func (l *Listener) Close() error {
+	l.unregister()
+	return l.stop()
+}
+
+// stop closes the underlying listener once.
+func (l *Listener) stop() error {
+	l.Lock()
+	if l.closed {
+		l.Unlock()
+		return nil
+	}
+	l.closed = true
+	l.Unlock()
+	return l.Listener.Close()
+}
+
+// Active returns the number of active connections.
+func (l *Listener) Active() int {
+	l.Lock()
+	defer l.Unlock()
+	return len(l.conns)
+}
+
+// Drain stops accepting connections and waits for the active connections
+// to be closed. If the timeout or the context expires first then the
+// remaining connections are closed and the context's error is returned.
+func (l *Listener) Drain(ctx context.Context) error {
+	l.stop()
+
+	if l.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+	}
+
+	l.Lock()
+	if len(l.conns) == 0 {
+		l.Unlock()
+		return nil
+	}
+	if l.idle == nil {
+		l.idle = make(chan struct{})
+	}
+	idle := l.idle
+	l.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.Lock()
+	conns := make([]*conn, 0, len(l.conns))
+	for c := range l.conns {
+		conns = append(conns, c)
+	}
+	l.Unlock()
+	for _, c := range conns {
+		c.Close()
+	}
+	return ctx.Err()
+}
+
+// remove stops tracking a connection.
+func (l *Listener) remove(c *conn) {
+	l.Lock()
+	defer l.Unlock()
+	delete(l.conns, c)
+	if len(l.conns) == 0 && l.idle != nil {
+		close(l.idle)
+		l.idle = nil
+	}
+}
+
+// conn is a connection tracked by a Listener.
+type conn struct {
+	net.Conn
+	l    *Listener
+	once sync.Once
+}
+
+func (c *conn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() { c.l.remove(c) })
+	return err
+}
+
+// errClosed is returned by Accept once the Listener is draining.
+var errClosed = &net.OpError{Op: "accept", Err: errListenerClosed{}}
+
+type errListenerClosed struct{}
+
+func (errListenerClosed) Error() string   { return "use of closed network connection" }
+func (errListenerClosed) Timeout() bool   { return false }
+func (errListenerClosed) Temporary() bool { return false }
@@ -0,0 +1,176 @@
+/*
+Package otelgoodbye provides OpenTelemetry tracing of the shutdown performed
+by the goodbye package.
+
+The Tracer implements the goodbye.Logger interface, so it is installed with
+the goodbye.SetLogger function. The entire shutdown is recorded as a span
+named "goodbye.shutdown" with a child span for each exit handler. The spans
+are flushed before the process exits if the TracerProvider has a ForceFlush
+function, as the OpenTelemetry SDK's TracerProvider does.
+
+	t := otelgoodbye.New(tracerProvider, logger)
+	goodbye.SetLogger(t)
+*/
+package otelgoodbye
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// InstrumentationName is the name of the OpenTelemetry tracer used to
+// create the spans.
+const InstrumentationName = "github.com/thecodeteam/goodbye/otelgoodbye"
+
+// flusher is implemented by TracerProviders that can export their spans
+// on demand.
+type flusher interface {
+	ForceFlush(ctx context.Context) error
+}
+
+// Tracer is a goodbye.Logger that records the shutdown as OpenTelemetry
+// spans.
+type Tracer struct {
+	provider trace.TracerProvider
+	tracer   trace.Tracer
+	next     goodbye.Logger
+
+	root    trace.Span
+	rootCtx context.Context
+	started map[string]time.Time
+	lock    sync.Mutex
+}
+
+var _ goodbye.Logger = (*Tracer)(nil)
+
+// New returns a new Tracer that creates spans with the TracerProvider. The
+// messages logged to the Tracer are relayed to the next Logger if it is not
+// nil.
+func New(tp trace.TracerProvider, next goodbye.Logger) *Tracer {
+	return &Tracer{
+		provider: tp,
+		tracer:   tp.Tracer(InstrumentationName),
+		next:     next,
+		started:  map[string]time.Time{},
+	}
+}
+
+// Log implements the goodbye.Logger interface. The message is recorded as
+// a span and then relayed to the next Logger.
+func (t *Tracer) Log(ctx context.Context, level goodbye.Level, msg string, keyvals ...interface{}) {
+	t.record(ctx, msg, keyvals)
+	if t.next != nil {
+		t.next.Log(ctx, level, msg, keyvals...)
+	}
+}
+
+func (t *Tracer) record(ctx context.Context, msg string, keyvals []interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	switch msg {
+	case goodbye.MsgShutdownStarted:
+		t.rootCtx, t.root = t.tracer.Start(ctx, "goodbye.shutdown",
+			trace.WithAttributes(
+				attribute.String("goodbye.signal", str(keyvals, goodbye.KeySignal)),
+				attribute.Int("goodbye.exit_code", integer(keyvals, goodbye.KeyExitCode))))
+
+	case goodbye.MsgHandlerStarted:
+		t.started[str(keyvals, goodbye.KeyHandler)] = time.Now()
+
+	case goodbye.MsgHandlerFinished, goodbye.MsgHandlerFailed, goodbye.MsgHandlerTimedOut:
+		t.handlerSpan(msg, keyvals)
+
+	case goodbye.MsgGracePeriodExpired:
+		if t.root != nil {
+			t.root.SetStatus(codes.Error, "grace period expired")
+			if names, ok := value(keyvals, goodbye.KeyRunning).([]string); ok {
+				t.root.SetAttributes(attribute.StringSlice("goodbye.running", names))
+			}
+		}
+
+	case goodbye.MsgExiting:
+		if t.root == nil {
+			return
+		}
+		t.root.SetAttributes(
+			attribute.Int("goodbye.exit_code", integer(keyvals, goodbye.KeyExitCode)))
+		t.root.End()
+		t.root = nil
+		if f, ok := t.provider.(flusher); ok {
+			if err := f.ForceFlush(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "otelgoodbye: flush failed: %v\n", err)
+			}
+		}
+	}
+}
+
+// handlerSpan records a span for an exit handler that completed or timed
+// out. The caller must hold the lock.
+func (t *Tracer) handlerSpan(msg string, keyvals []interface{}) {
+	var (
+		name  = str(keyvals, goodbye.KeyHandler)
+		end   = time.Now()
+		start = end
+	)
+	if d, ok := value(keyvals, goodbye.KeyDuration).(time.Duration); ok {
+		start = end.Add(-d)
+	} else if s, ok := t.started[name]; ok {
+		start = s
+	}
+	delete(t.started, name)
+
+	ctx := t.rootCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	_, span := t.tracer.Start(ctx, "goodbye.handler "+name,
+		trace.WithTimestamp(start),
+		trace.WithAttributes(
+			attribute.String("goodbye.handler", name),
+			attribute.Int("goodbye.priority", integer(keyvals, goodbye.KeyPriority))))
+
+	switch msg {
+	case goodbye.MsgHandlerFailed:
+		if err, ok := value(keyvals, goodbye.KeyError).(error); ok {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+	case goodbye.MsgHandlerTimedOut:
+		span.SetStatus(codes.Error, goodbye.ErrTimeout.Error())
+	default:
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End(trace.WithTimestamp(end))
+}
+
+// value returns the value associated with the key.
+func value(keyvals []interface{}, key string) interface{} {
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == key {
+			return keyvals[i+1]
+		}
+	}
+	return nil
+}
+
+func str(keyvals []interface{}, key string) string {
+	if v := value(keyvals, key); v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+func integer(keyvals []interface{}, key string) int {
+	v, _ := value(keyvals, key).(int)
+	return v
+}
@@ -0,0 +1,25 @@
+/*
+Package pid1 lets a process that uses the goodbye package run as the init
+process of a container.
+
+The kernel does not apply the default action of a signal, such as
+terminating the process on SIGTERM, to the init process of a PID namespace,
+and it reparents orphaned processes to the init process, which must reap
+them to prevent zombie processes from accumulating. The Enable function
+makes a process that is PID 1 trap the default signals with the goodbye
+package, forward the signal that causes the shutdown to the other
+processes in the namespace, and reap the zombie processes:
+
+	if _, err := pid1.Enable(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+Zombie reaping collects the exit status of every child process, so the Wait
+function of an os/exec.Cmd started by the process may return an error once
+reaping is enabled. Processes that start children and wait for them should
+use the WithReaping option to disable reaping.
+
+Enable does nothing on Windows, js/wasm, Plan 9, and the other platforms
+on which the syscall package cannot reap zombie processes.
+*/
+package pid1
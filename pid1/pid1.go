@@ -0,0 +1,56 @@
+package pid1
+
+import "github.com/thecodeteam/goodbye"
+
+// Option configures Enable.
+type Option func(c *config)
+
+type config struct {
+	m       *goodbye.Manager
+	force   bool
+	reap    bool
+	forward bool
+}
+
+func newConfig(opts []Option) config {
+	c := config{m: goodbye.Default(), reap: true, forward: true}
+	for _, o := range opts {
+		o(&c)
+	}
+	return c
+}
+
+// WithManager returns an Option that traps the signals with the Manager
+// instead of the default Manager.
+func WithManager(m *goodbye.Manager) Option {
+	return func(c *config) {
+		c.m = m
+	}
+}
+
+// WithForce returns an Option that enables the init mode even if the
+// process is not PID 1. Such a process reaps its zombie children but does
+// not forward signals, since a process that is not the init process of
+// its PID namespace would send them to every process the user may signal.
+func WithForce(force bool) Option {
+	return func(c *config) {
+		c.force = force
+	}
+}
+
+// WithReaping returns an Option that sets whether zombie processes are
+// reaped. It is enabled by default.
+func WithReaping(enabled bool) Option {
+	return func(c *config) {
+		c.reap = enabled
+	}
+}
+
+// WithForwarding returns an Option that sets whether trapped signals are
+// forwarded to the other processes in the PID namespace. It is enabled by
+// default, and only takes effect when the process is PID 1.
+func WithForwarding(enabled bool) Option {
+	return func(c *config) {
+		c.forward = enabled
+	}
+}
@@ -0,0 +1,11 @@
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package pid1
+
+import "context"
+
+// Enable does nothing on this platform, which does not have an init
+// process. The returned boolean is always false.
+func Enable(ctx context.Context, opts ...Option) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,72 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package pid1
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Enable enables the init mode if the process is PID 1. The Manager's
+// Notify function is invoked with the default signals if it has not
+// trapped any signals. The returned boolean is true if the init mode was
+// enabled.
+//
+// When forwarding is enabled and the process is PID 1, the signal that
+// causes the shutdown is sent to every other process in the PID namespace
+// before the exit handlers are executed. A process that is not PID 1, but
+// whose init mode is enabled with WithForce, does not forward signals.
+func Enable(ctx context.Context, opts ...Option) (bool, error) {
+	c := newConfig(opts)
+	isInit := os.Getpid() == 1
+	if !isInit && !c.force {
+		return false, nil
+	}
+
+	if len(c.m.State().Trapped) == 0 {
+		if err := c.m.NotifySignals(ctx); err != nil {
+			return false, err
+		}
+	}
+
+	// Only the init process may forward to a pid of -1, which otherwise
+	// refers to every process the user may signal.
+	if c.forward && isInit {
+		c.m.BeforeAll(func(ctx context.Context, s os.Signal) {
+			if n, ok := s.(syscall.Signal); ok {
+				syscall.Kill(-1, n)
+			}
+		})
+	}
+
+	if c.reap {
+		go reap()
+	}
+	return true, nil
+}
+
+// reap reaps zombie processes each time SIGCHLD is received.
+func reap() {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGCHLD)
+	for {
+		reapAll()
+		<-sigc
+	}
+}
+
+// reapAll reaps the zombie processes that exist.
+func reapAll() {
+	for {
+		var ws syscall.WaitStatus
+		pid, err := syscall.Wait4(-1, &ws, syscall.WNOHANG, nil)
+		if err == syscall.EINTR {
+			continue
+		}
+		if pid <= 0 || err != nil {
+			return
+		}
+	}
+}
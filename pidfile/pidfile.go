@@ -0,0 +1,130 @@
+/*
+Package pidfile writes a file that contains the process's ID and removes it
+when the process exits with the goodbye package.
+
+	if err := pidfile.Write("/run/myapp.pid"); err != nil {
+		log.Fatal(err)
+	}
+
+A pidfile that names a process that is no longer running is considered
+stale and is replaced.
+*/
+package pidfile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// Priority is the priority of the exit handler that removes the pidfile.
+// It is after PhaseClose so that the pidfile is removed once the process's
+// other resources have been released.
+var Priority = goodbye.PhaseClose.Priority + 1000
+
+// RunningError is returned by Write if the pidfile names a process that is
+// running.
+type RunningError struct {
+	// Path is the path of the pidfile.
+	Path string
+
+	// PID is the ID of the running process.
+	PID int
+}
+
+func (e *RunningError) Error() string {
+	return fmt.Sprintf("pidfile: %s: process %d is running", e.Path, e.PID)
+}
+
+// Option configures Write.
+type Option func(c *config)
+
+type config struct {
+	m *goodbye.Manager
+}
+
+// WithManager returns an Option that registers the exit handler with the
+// Manager instead of the default Manager.
+func WithManager(m *goodbye.Manager) Option {
+	return func(c *config) {
+		c.m = m
+	}
+}
+
+// Write writes the ID of the process to the file at path and registers an
+// exit handler that removes the file. If the file exists and names a
+// process that is running then a *RunningError is returned. If the file
+// names a process that is not running then it is replaced.
+func Write(path string, opts ...Option) error {
+	c := config{m: goodbye.Default()}
+	for _, o := range opts {
+		o(&c)
+	}
+
+	if pid, err := Read(path); err == nil {
+		if pid != os.Getpid() && running(pid) {
+			return &RunningError{Path: path, PID: pid}
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		if _, ok := err.(*strconv.NumError); !ok {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	// The file is created exclusively so that two processes that both
+	// found a stale pidfile do not both believe they own it.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(f, "%d\n", os.Getpid()); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	c.m.RegisterNamedFunc("pidfile "+path, func(ctx context.Context, s os.Signal) error {
+		return Remove(path)
+	}, Priority)
+	return nil
+}
+
+// Read returns the process ID in the file at path.
+func Read(path string) (int, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(bytes.TrimSpace(buf)))
+}
+
+// Remove removes the file at path if it contains the ID of the process.
+// It returns nil if the file does not exist.
+func Remove(path string) error {
+	pid, err := Read(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err == nil && pid != os.Getpid() {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+// +build plan9
+
+package pidfile
+
+import (
+	"os"
+	"strconv"
+)
+
+// running returns true if the process with the ID exists, which is the
+// case if the process has a directory in /proc.
+func running(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	_, err := os.Stat("/proc/" + strconv.Itoa(pid))
+	return err == nil
+}
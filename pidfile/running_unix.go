@@ -0,0 +1,15 @@
+// +build !windows,!plan9
+
+package pidfile
+
+import "syscall"
+
+// running returns true if the process with the ID exists. A process owned
+// by another user is reported as running.
+func running(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
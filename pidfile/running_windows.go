@@ -0,0 +1,18 @@
+// +build windows
+
+package pidfile
+
+import "os"
+
+// running returns true if the process with the ID exists.
+func running(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
@@ -0,0 +1,48 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Package slogadapter provides a goodbye.Logger that logs with log/slog.
+*/
+package slogadapter
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+type logger struct {
+	l *slog.Logger
+}
+
+// New returns a goodbye.Logger that logs with the slog.Logger. If l is nil
+// then the logger returned by slog.Default is used each time a message is
+// logged.
+func New(l *slog.Logger) goodbye.Logger {
+	return &logger{l: l}
+}
+
+// Log logs the message with the slog.Logger. The keyvals are provided to
+// the slog.Logger as its args.
+func (a *logger) Log(ctx context.Context, level goodbye.Level, msg string, keyvals ...interface{}) {
+	l := a.l
+	if l == nil {
+		l = slog.Default()
+	}
+	l.Log(ctx, Level(level), msg, keyvals...)
+}
+
+// Level returns the slog.Level that corresponds to the goodbye.Level.
+func Level(level goodbye.Level) slog.Level {
+	switch level {
+	case goodbye.LevelDebug:
+		return slog.LevelDebug
+	case goodbye.LevelInfo:
+		return slog.LevelInfo
+	case goodbye.LevelWarn:
+		return slog.LevelWarn
+	}
+	return slog.LevelError
+}
@@ -0,0 +1,147 @@
+/*
+Package systemd integrates the goodbye package with the systemd service
+manager.
+
+The Watchdog function keeps the systemd watchdog satisfied while the exit
+handlers run, so that a long drain is not mistaken for a hung process:
+
+	if _, err := systemd.Watchdog(); err != nil {
+		log.Fatal(err)
+	}
+*/
+package systemd
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// The names of the environment variables set by systemd.
+const (
+	// EnvNotifySocket is the path of the socket to which notifications are
+	// sent.
+	EnvNotifySocket = "NOTIFY_SOCKET"
+
+	// EnvWatchdogUsec is the watchdog interval in microseconds.
+	EnvWatchdogUsec = "WATCHDOG_USEC"
+
+	// EnvWatchdogPID is the ID of the process that is expected to send
+	// watchdog notifications.
+	EnvWatchdogPID = "WATCHDOG_PID"
+)
+
+// Notify sends the state to systemd, ex. "WATCHDOG=1". It returns false if
+// the process was not started by systemd with a notification socket.
+func Notify(state string) (bool, error) {
+	name := os.Getenv(EnvNotifySocket)
+	if name == "" {
+		return false, nil
+	}
+	if name[0] == '@' {
+		name = "\x00" + name[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: name, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WatchdogInterval returns the interval at which systemd expects watchdog
+// notifications, or zero if the watchdog is not enabled for the process.
+func WatchdogInterval() (time.Duration, error) {
+	s := os.Getenv(EnvWatchdogUsec)
+	if s == "" {
+		return 0, nil
+	}
+	usec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if usec <= 0 {
+		return 0, nil
+	}
+	if p := os.Getenv(EnvWatchdogPID); p != "" {
+		pid, err := strconv.Atoi(p)
+		if err != nil {
+			return 0, err
+		}
+		if pid != os.Getpid() {
+			return 0, nil
+		}
+	}
+	return time.Duration(usec) * time.Microsecond, nil
+}
+
+// Option configures Watchdog.
+type Option func(c *config)
+
+type config struct {
+	m *goodbye.Manager
+}
+
+// WithManager returns an Option that installs the hooks on the Manager
+// instead of the default Manager.
+func WithManager(m *goodbye.Manager) Option {
+	return func(c *config) {
+		c.m = m
+	}
+}
+
+// Watchdog notifies systemd with "STOPPING=1" as soon as the shutdown
+// begins and then sends "WATCHDOG=1" at half of the watchdog interval until
+// the exit handlers complete. The notifications begin when the Manager's
+// Done channel is closed, so they also cover the critical handlers, the
+// pre-stop delay, and the inhibitors that precede the other handlers. The
+// returned boolean is false if the watchdog is not enabled for the process,
+// in which case only the "STOPPING=1" notification is sent.
+//
+// Watchdog covers the next shutdown of the Manager, and should be invoked
+// again if the Manager is reset.
+func Watchdog(opts ...Option) (bool, error) {
+	c := config{m: goodbye.Default()}
+	for _, o := range opts {
+		o(&c)
+	}
+
+	interval, err := WatchdogInterval()
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		stop     = make(chan struct{})
+		stopOnce sync.Once
+	)
+	c.m.AfterAll(func(ctx context.Context, r goodbye.ShutdownReport) {
+		stopOnce.Do(func() { close(stop) })
+	})
+	go func(done <-chan struct{}) {
+		<-done
+		Notify("STOPPING=1")
+		if interval <= 0 {
+			return
+		}
+		t := time.NewTicker(interval / 2)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				Notify("WATCHDOG=1")
+			case <-stop:
+				return
+			}
+		}
+	}(c.m.Done())
+	return interval > 0, nil
+}
@@ -0,0 +1,77 @@
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package systemd
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+func TestWatchdogPreStopDelay(t *testing.T) {
+	addr := &net.UnixAddr{Name: filepath.Join(t.TempDir(), "notify"), Net: "unixgram"}
+	conn, err := net.ListenUnixgram("unixgram", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	t.Setenv(EnvNotifySocket, addr.Name)
+	t.Setenv(EnvWatchdogUsec, "100000")
+	t.Setenv(EnvWatchdogPID, "")
+
+	m := goodbye.New()
+	m.SetExiter(func(int) {})
+	m.SetPreStopDelay(300 * time.Millisecond)
+	if ok, err := Watchdog(WithManager(m)); !ok || err != nil {
+		t.Fatalf("Watchdog = %t, %v, want true, nil", ok, err)
+	}
+
+	// The notifications received before the pre-stop delay ends must keep
+	// the 100ms watchdog satisfied.
+	type note struct {
+		state string
+		at    time.Time
+	}
+	notes := make(chan note, 64)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				close(notes)
+				return
+			}
+			notes <- note{string(buf[:n]), time.Now()}
+		}
+	}()
+
+	start := time.Now()
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	var (
+		states []string
+		last   = start
+	)
+	for n := range notes {
+		if gap := n.at.Sub(last); gap > 100*time.Millisecond {
+			t.Errorf("%s received %s after the previous notification", n.state, gap)
+		}
+		states, last = append(states, n.state), n.at
+	}
+	if len(states) < 3 || states[0] != "STOPPING=1" {
+		t.Fatalf("notifications = %q, want STOPPING=1 followed by WATCHDOG=1", states)
+	}
+	for _, s := range states[1:] {
+		if !strings.HasPrefix(s, "WATCHDOG=1") {
+			t.Fatalf("notifications = %q, want STOPPING=1 followed by WATCHDOG=1", states)
+		}
+	}
+}
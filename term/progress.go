@@ -0,0 +1,86 @@
+package term
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// ShowProgress renders the progress of the shutdown to the standard error
+// if it is a terminal, so that the user understands why the program does
+// not exit as soon as Ctrl-C is pressed:
+//
+//	http-server… done (1.2s)
+//	logs… done (0.1s)
+//
+// A line is rendered for each named exit handler when it begins executing
+// and is completed when the handler returns. Unnamed handlers are not
+// rendered. The WithOutput option may be used to render the progress to
+// another writer regardless of whether it is a terminal.
+func ShowProgress(opts ...Option) {
+	c := config{m: goodbye.Default()}
+	if isTerminal(os.Stderr) {
+		c.out = os.Stderr
+	}
+	for _, o := range opts {
+		o(&c)
+	}
+	if c.out == nil {
+		return
+	}
+
+	p := &progress{w: c.out}
+	c.m.BeforeEach(p.begin)
+	c.m.AfterEach(p.end)
+}
+
+// progress renders the progress of the exit handlers. Handlers that share
+// a priority level may be executed concurrently, so the handler whose line
+// is being rendered is tracked in order to start a new line when another
+// handler begins or ends.
+type progress struct {
+	w       io.Writer
+	current string
+	sync.Mutex
+}
+
+func (p *progress) begin(ctx context.Context, h goodbye.HandlerInfo) {
+	if h.Name == "" {
+		return
+	}
+	p.Lock()
+	defer p.Unlock()
+	if p.current != "" {
+		fmt.Fprintln(p.w)
+	}
+	p.current = h.Name
+	fmt.Fprintf(p.w, "%s…", h.Name)
+}
+
+func (p *progress) end(ctx context.Context, r goodbye.HandlerReport) {
+	if r.Name == "" {
+		return
+	}
+	p.Lock()
+	defer p.Unlock()
+	if p.current != r.Name {
+		if p.current != "" {
+			fmt.Fprintln(p.w)
+		}
+		fmt.Fprintf(p.w, "%s…", r.Name)
+	}
+	p.current = ""
+
+	d := r.Duration.Round(100 * time.Millisecond)
+	switch r.Status {
+	case goodbye.HandlerOK:
+		fmt.Fprintf(p.w, " done (%s)\n", d)
+	default:
+		fmt.Fprintf(p.w, " %s (%s)\n", r.Status, d)
+	}
+}
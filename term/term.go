@@ -0,0 +1,165 @@
+/*
+Package term restores the state of the terminal when the process exits with
+the goodbye package, so that a command line or full-screen terminal program
+that is interrupted with Ctrl-C does not leave the user's terminal in raw
+mode, with echo disabled, or with the cursor hidden.
+
+The state of the terminal must be saved before the program changes it:
+
+	if err := term.Restore(); err != nil {
+		log.Fatal(err)
+	}
+	// Put the terminal into raw mode.
+
+Full-screen programs, such as those written with bubbletea or tcell, may
+use RestoreScreen instead, which also leaves the alternate screen, disables
+mouse tracking, and resets the colors:
+
+	if err := term.RestoreScreen(); err != nil {
+		log.Fatal(err)
+	}
+
+Restore and RestoreScreen do nothing if the standard input is not a
+terminal.
+
+Interactive programs may also use ShowProgress to render the progress of the
+shutdown, so that the user understands why the program does not exit as soon
+as Ctrl-C is pressed. It returns
+goodbye.ErrNotSupported on js/wasm, Plan 9, and the other platforms on
+which the syscall package cannot query the state of a terminal.
+*/
+package term
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// Priority is the priority of the exit handler that restores the terminal.
+// It is in the critical phase so that the terminal is restored before the
+// shutdown is delayed by the pre-stop delay or the inhibitors.
+var Priority = goodbye.PhaseCritical.Priority
+
+// The escape sequences written by the exit handlers.
+const (
+	// ShowCursor makes the cursor visible.
+	ShowCursor = "\x1b[?25h"
+
+	// DisableMouse disables the X10, button-event, any-event, SGR, and
+	// urxvt mouse tracking modes.
+	DisableMouse = "\x1b[?1000l\x1b[?1002l\x1b[?1003l\x1b[?1006l\x1b[?1015l"
+
+	// ResetAttributes resets the colors and the other character
+	// attributes.
+	ResetAttributes = "\x1b[0m"
+
+	// ExitAltScreen leaves the alternate screen and restores the contents
+	// of the main screen.
+	ExitAltScreen = "\x1b[?1049l"
+)
+
+// Option configures Restore, RestoreScreen, and ShowProgress.
+type Option func(c *config)
+
+type config struct {
+	m   *goodbye.Manager
+	in  *os.File
+	out io.Writer
+}
+
+// WithManager returns an Option that registers the exit handler with the
+// Manager instead of the default Manager.
+func WithManager(m *goodbye.Manager) Option {
+	return func(c *config) {
+		c.m = m
+	}
+}
+
+// WithFile returns an Option that saves and restores the state of the
+// terminal attached to the file instead of the standard input.
+func WithFile(f *os.File) Option {
+	return func(c *config) {
+		c.in = f
+	}
+}
+
+// WithOutput returns an Option that writes the escape sequences that reset
+// the terminal, such as ShowCursor, or the progress of the shutdown to w
+// instead of the standard output or standard error. A nil writer disables
+// the output.
+func WithOutput(w io.Writer) Option {
+	return func(c *config) {
+		c.out = w
+	}
+}
+
+// Restore saves the state of the terminal attached to the standard input
+// and registers an exit handler that restores it. The exit handler also
+// makes the cursor visible if the standard output is a terminal.
+func Restore(opts ...Option) error {
+	return restore("terminal", ShowCursor, opts)
+}
+
+// RestoreScreen saves the state of the terminal attached to the standard
+// input and registers an exit handler that disables mouse tracking,
+// resets the colors, makes the cursor visible, and leaves the alternate
+// screen before it restores the state of the terminal. On Windows the
+// modes of both the standard input and output consoles are restored.
+//
+// The exit handler is registered in the critical phase, so it is executed
+// before the handlers registered with any other priority, and the user is
+// returned to a usable terminal while the rest of the shutdown proceeds.
+func RestoreScreen(opts ...Option) error {
+	return restore("terminal screen",
+		DisableMouse+ResetAttributes+ShowCursor+ExitAltScreen, opts)
+}
+
+// restore saves the state of the terminal and registers an exit handler
+// with the specified name that writes the escape sequences and restores
+// the state of the terminal.
+func restore(name, seq string, opts []Option) error {
+	c := config{m: goodbye.Default(), in: os.Stdin}
+	if isTerminal(os.Stdout) {
+		c.out = os.Stdout
+	}
+	for _, o := range opts {
+		o(&c)
+	}
+
+	in, err := getState(c.in)
+	if err != nil {
+		if err == errNotTerminal {
+			return nil
+		}
+		return err
+	}
+
+	// The state of the output is saved as well since it is a separate
+	// console on Windows whose mode determines whether escape sequences
+	// are processed.
+	var out *state
+	f, _ := c.out.(*os.File)
+	if f != nil {
+		out, _ = getState(f)
+	}
+
+	c.m.RegisterNamedFunc(name, func(ctx context.Context, s os.Signal) error {
+		if c.out != nil {
+			io.WriteString(c.out, seq)
+		}
+		if out != nil {
+			setState(f, out)
+		}
+		return setState(c.in, in)
+	}, Priority)
+	return nil
+}
+
+// isTerminal returns true if the file is a terminal.
+func isTerminal(f *os.File) bool {
+	_, err := getState(f)
+	return err == nil
+}
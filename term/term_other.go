@@ -0,0 +1,26 @@
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd,!windows
+
+package term
+
+import (
+	"errors"
+	"os"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// errNotTerminal is returned by getState if the file is not a terminal.
+var errNotTerminal = errors.New("term: not a terminal")
+
+// state is the saved state of a terminal.
+type state struct{}
+
+// getState returns goodbye.ErrNotSupported since the syscall package
+// cannot query the state of a terminal on the operating system.
+func getState(f *os.File) (*state, error) {
+	return nil, goodbye.ErrNotSupported
+}
+
+func setState(f *os.File, st *state) error {
+	return goodbye.ErrNotSupported
+}
@@ -0,0 +1,40 @@
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package term
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// errNotTerminal is returned by getState if the file is not a terminal.
+var errNotTerminal = errors.New("term: not a terminal")
+
+// state is the saved state of a terminal.
+type state struct {
+	termios syscall.Termios
+}
+
+func getState(f *os.File) (*state, error) {
+	var st state
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL,
+		f.Fd(), ioctlGetTermios, uintptr(unsafe.Pointer(&st.termios)))
+	switch errno {
+	case 0:
+		return &st, nil
+	case syscall.ENOTTY, syscall.EINVAL, syscall.ENODEV:
+		return nil, errNotTerminal
+	}
+	return nil, errno
+}
+
+func setState(f *os.File, st *state) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL,
+		f.Fd(), ioctlSetTermios, uintptr(unsafe.Pointer(&st.termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
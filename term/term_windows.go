@@ -0,0 +1,36 @@
+// +build windows
+
+package term
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+var procSetConsoleMode = syscall.NewLazyDLL(
+	"kernel32.dll").NewProc("SetConsoleMode")
+
+// errNotTerminal is returned by getState if the file is not a console.
+var errNotTerminal = errors.New("term: not a terminal")
+
+// state is the saved mode of a console.
+type state struct {
+	mode uint32
+}
+
+func getState(f *os.File) (*state, error) {
+	var st state
+	if err := syscall.GetConsoleMode(syscall.Handle(f.Fd()), &st.mode); err != nil {
+		return nil, errNotTerminal
+	}
+	return &st, nil
+}
+
+func setState(f *os.File, st *state) error {
+	r, _, err := procSetConsoleMode.Call(f.Fd(), uintptr(st.mode))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Package tiny is a minimal variant of the goodbye package for TinyGo and
+other embedded environments. It does not start any goroutines, allocate
+maps, or depend on the os/signal package, and its exit handlers are kept
+in a fixed-size table of MaxHandlers entries.
+
+Since the package does not trap signals, the program's own event loop
+invokes the Signal function when it receives a signal or an equivalent
+event, such as a button press:
+
+	tiny.Register(func(s os.Signal) {
+		flushLog()
+	})
+
+	for {
+		if shutdownRequested() {
+			tiny.Signal(os.Interrupt, 0)
+		}
+		work()
+	}
+*/
+package tiny
+
+import (
+	"errors"
+	"os"
+	"sync"
+)
+
+// MaxHandlers is the number of exit handlers that may be registered.
+const MaxHandlers = 16
+
+// ErrFull is returned when MaxHandlers exit handlers are registered.
+var ErrFull = errors.New("tiny: handler table is full")
+
+// Handler is an exit handler. The signal is nil when the process exits as
+// a result of the Exit function.
+type Handler func(s os.Signal)
+
+type entry struct {
+	f        Handler
+	priority int
+}
+
+var (
+	table  [MaxHandlers]entry
+	n      int
+	exited bool
+	exiter = os.Exit
+	lock   sync.Mutex
+)
+
+// Register registers an exit handler with a priority of 0. ErrFull is
+// returned if the handler table is full.
+func Register(f Handler) error {
+	return RegisterWithPriority(f, 0)
+}
+
+// RegisterWithPriority registers an exit handler with the specified
+// priority. Handlers are executed in ascending order of priority, and
+// handlers with the same priority are executed in the order in which they
+// were registered. ErrFull is returned if the handler table is full.
+func RegisterWithPriority(f Handler, priority int) error {
+	lock.Lock()
+	defer lock.Unlock()
+	if n == MaxHandlers {
+		return ErrFull
+	}
+	i := n
+	for i > 0 && table[i-1].priority > priority {
+		table[i] = table[i-1]
+		i--
+	}
+	table[i] = entry{f: f, priority: priority}
+	n++
+	return nil
+}
+
+// Exit executes the exit handlers and exits the process with the exit
+// code. The handlers are executed at most once, and subsequent invocations
+// of Exit or Signal exit the process without executing them.
+func Exit(code int) {
+	handle(nil, code)
+}
+
+// Signal executes the exit handlers with the signal and exits the process
+// with the exit code. Please see the Exit function.
+func Signal(s os.Signal, code int) {
+	handle(s, code)
+}
+
+func handle(s os.Signal, code int) {
+	lock.Lock()
+	exit := exiter
+	if exited {
+		lock.Unlock()
+		exit(code)
+		return
+	}
+	exited = true
+	handlers, count := table, n
+	lock.Unlock()
+
+	for i := 0; i < count; i++ {
+		handlers[i].f(s)
+	}
+	exit(code)
+}
+
+// SetExiter sets the function used to exit the process after the exit
+// handlers have completed. The default value is os.Exit. A nil value
+// restores the default.
+func SetExiter(f func(code int)) {
+	if f == nil {
+		f = os.Exit
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	exiter = f
+}
+
+// Reset removes the registered exit handlers and re-arms the package so
+// that a subsequent invocation of Exit or Signal executes the exit
+// handlers registered after Reset.
+func Reset() {
+	lock.Lock()
+	defer lock.Unlock()
+	table = [MaxHandlers]entry{}
+	n, exited = 0, false
+}
@@ -0,0 +1,29 @@
+/*
+Package upgrade restarts a process without downtime by starting a new copy
+of its binary, passing it the process's listeners, and executing the old
+process's exit handlers only once the new process is ready.
+
+	set, err := listenfd.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	l, err := set.Listen("http", "tcp", ":8080")
+	if err != nil {
+		log.Fatal(err)
+	}
+	go http.Serve(l, mux)
+
+	u := upgrade.New(set)
+	u.OnSignal(syscall.SIGHUP)
+
+	// Tell the old process, if any, that this process is ready.
+	if err := upgrade.Ready(); err != nil {
+		log.Fatal(err)
+	}
+
+An upgrade that fails, ex. because the new binary exits before it is ready,
+leaves the old process running.
+
+Upgrades are not supported on Windows.
+*/
+package upgrade
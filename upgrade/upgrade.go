@@ -0,0 +1,208 @@
+// +build !windows
+
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/thecodeteam/goodbye"
+	"github.com/thecodeteam/goodbye/listenfd"
+)
+
+// EnvReadyFD is the name of the environment variable that holds the file
+// descriptor to which the new process writes when it is ready.
+const EnvReadyFD = "GOODBYE_UPGRADE_READY_FD"
+
+// DefaultReadyTimeout is the default amount of time the new process is
+// given to become ready.
+const DefaultReadyTimeout = time.Minute
+
+var (
+	// ErrInProgress is returned by Upgrade if an upgrade is in progress.
+	ErrInProgress = errors.New("upgrade: upgrade in progress")
+
+	// ErrNotReady is returned by Upgrade if the new process exits before
+	// it is ready.
+	ErrNotReady = errors.New("upgrade: new process exited before it was ready")
+)
+
+// Option configures an Upgrader.
+type Option func(u *Upgrader)
+
+// WithManager returns an Option that executes the exit handlers of the
+// Manager instead of the default Manager.
+func WithManager(m *goodbye.Manager) Option {
+	return func(u *Upgrader) {
+		u.m = m
+	}
+}
+
+// WithReadyTimeout returns an Option that sets the amount of time the new
+// process is given to become ready. The default value is
+// DefaultReadyTimeout.
+func WithReadyTimeout(d time.Duration) Option {
+	return func(u *Upgrader) {
+		u.timeout = d
+	}
+}
+
+// WithCommand returns an Option that sets the function that returns the
+// command used to start the new process. The default command executes the
+// process's binary with the same arguments and environment.
+func WithCommand(f func() (*exec.Cmd, error)) Option {
+	return func(u *Upgrader) {
+		u.command = f
+	}
+}
+
+// Upgrader upgrades a process.
+type Upgrader struct {
+	m       *goodbye.Manager
+	set     *listenfd.Set
+	timeout time.Duration
+	command func() (*exec.Cmd, error)
+
+	upgrading bool
+	sync.Mutex
+}
+
+// New returns an Upgrader that passes the listeners in the set to the new
+// process.
+func New(set *listenfd.Set, opts ...Option) *Upgrader {
+	u := &Upgrader{
+		m:       goodbye.Default(),
+		set:     set,
+		timeout: DefaultReadyTimeout,
+		command: defaultCommand,
+	}
+	for _, o := range opts {
+		o(u)
+	}
+	return u
+}
+
+func defaultCommand() (*exec.Cmd, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd, nil
+}
+
+// OnSignal registers an action that upgrades the process each time the
+// signal is received. Errors are written to stderr.
+//
+// The returned function removes the action when invoked.
+func (u *Upgrader) OnSignal(sig os.Signal) func() {
+	return u.m.RegisterAction(sig, "upgrade", func(ctx context.Context, s os.Signal) {
+		go func() {
+			if err := u.Upgrade(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "upgrade: %v\n", err)
+			}
+		}()
+	})
+}
+
+// Upgrade starts the new process and waits for it to become ready. Once it
+// is ready the Manager's exit handlers are executed and the process exits
+// with an exit code of zero. If the new process exits or does not become
+// ready before the timeout or the context expires then an error is
+// returned and the old process continues to run.
+func (u *Upgrader) Upgrade(ctx context.Context) error {
+	u.Lock()
+	if u.upgrading {
+		u.Unlock()
+		return ErrInProgress
+	}
+	u.upgrading = true
+	u.Unlock()
+	defer func() {
+		u.Lock()
+		u.upgrading = false
+		u.Unlock()
+	}()
+
+	cmd, err := u.command()
+	if err != nil {
+		return err
+	}
+	if err := u.set.Export(cmd); err != nil {
+		return err
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	cmd.Env = append(cmd.Env,
+		EnvReadyFD+"="+strconv.Itoa(listenfd.StartFD+len(cmd.ExtraFiles)))
+	cmd.ExtraFiles = append(cmd.ExtraFiles, w)
+
+	err = cmd.Start()
+	for _, f := range cmd.ExtraFiles {
+		f.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	// The read returns a byte when the new process is ready, and returns
+	// an error when the new process exits with the pipe still open.
+	readyc := make(chan error, 1)
+	go func() {
+		var b [1]byte
+		_, err := r.Read(b[:])
+		readyc <- err
+	}()
+
+	if u.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, u.timeout)
+		defer cancel()
+	}
+
+	select {
+	case err := <-readyc:
+		if err != nil {
+			cmd.Wait()
+			return ErrNotReady
+		}
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		cmd.Wait()
+		return ctx.Err()
+	}
+
+	cmd.Process.Release()
+	u.m.Exit(ctx, 0)
+	return nil
+}
+
+// Ready notifies the old process that this process is ready, so that the
+// old process executes its exit handlers and exits. It does nothing if the
+// process was not started by an Upgrader.
+func Ready() error {
+	s := os.Getenv(EnvReadyFD)
+	if s == "" {
+		return nil
+	}
+	os.Unsetenv(EnvReadyFD)
+	fd, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("upgrade: invalid %s: %s", EnvReadyFD, s)
+	}
+	f := os.NewFile(uintptr(fd), "ready")
+	defer f.Close()
+	_, err = f.Write([]byte{1})
+	return err
+}
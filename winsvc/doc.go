@@ -0,0 +1,21 @@
+/*
+Package winsvc runs a process that exits with the goodbye package as a
+Windows service.
+
+	if ok, _ := svc.IsWindowsService(); ok {
+		code, err := winsvc.Run("myservice")
+		if err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(code)
+	}
+
+The Stop and Shutdown commands sent by the Service Control Manager execute
+the exit handlers, and the service reports that it is stopping, with an
+advancing checkpoint, until the handlers complete. The Pause and Continue
+commands are delivered as the PauseSignal and ContinueSignal signals to the
+functions registered with the goodbye.Observe function.
+
+The package is only available on Windows.
+*/
+package winsvc
@@ -0,0 +1,196 @@
+// +build windows
+
+package winsvc
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+// DefaultCheckpoint is the default interval at which the service reports
+// its progress while the exit handlers are executed.
+const DefaultCheckpoint = time.Second
+
+// Signal is a Service Control Manager command delivered as a signal.
+type Signal svc.Cmd
+
+var (
+	// PauseSignal is triggered when the service is paused.
+	PauseSignal = Signal(svc.Pause)
+
+	// ContinueSignal is triggered when the service is continued.
+	ContinueSignal = Signal(svc.Continue)
+)
+
+// Signal is a no-op that allows a Signal to be an os.Signal.
+func (s Signal) Signal() {}
+
+func (s Signal) String() string {
+	switch svc.Cmd(s) {
+	case svc.Pause:
+		return "SERVICE_CONTROL_PAUSE"
+	case svc.Continue:
+		return "SERVICE_CONTROL_CONTINUE"
+	}
+	return "SERVICE_CONTROL"
+}
+
+// Option configures a Service.
+type Option func(s *Service)
+
+// WithManager returns an Option that executes the exit handlers of the
+// Manager instead of the default Manager.
+func WithManager(m *goodbye.Manager) Option {
+	return func(s *Service) {
+		s.m = m
+	}
+}
+
+// WithSignal returns an Option that sets the signal that is triggered when
+// the service is stopped or the computer is shutting down. If the signal
+// is not trapped by the Manager then the Manager's Exit function is
+// invoked instead. The default value is SIGTERM.
+func WithSignal(sig os.Signal) Option {
+	return func(s *Service) {
+		s.sig = sig
+	}
+}
+
+// WithCheckpoint returns an Option that sets the interval at which the
+// service reports its progress to the Service Control Manager while the
+// exit handlers are executed. The default value is DefaultCheckpoint.
+func WithCheckpoint(d time.Duration) Option {
+	return func(s *Service) {
+		s.checkpoint = d
+	}
+}
+
+// WithPauseContinue returns an Option that sets whether the service accepts
+// the Pause and Continue commands.
+func WithPauseContinue(enabled bool) Option {
+	return func(s *Service) {
+		s.pause = enabled
+	}
+}
+
+// Service is an svc.Handler that executes the exit handlers when the
+// service is stopped.
+type Service struct {
+	m          *goodbye.Manager
+	sig        os.Signal
+	checkpoint time.Duration
+	pause      bool
+	code       int
+}
+
+// New returns a Service. The Manager's exiter is replaced so that the
+// service reports that it has stopped after the exit handlers complete
+// instead of exiting the process.
+func New(opts ...Option) *Service {
+	s := &Service{
+		m:          goodbye.Default(),
+		sig:        syscall.SIGTERM,
+		checkpoint: DefaultCheckpoint,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Run runs the process as the named service and returns the exit code
+// with which the process should exit once the service stops.
+func Run(name string, opts ...Option) (int, error) {
+	s := New(opts...)
+	if err := svc.Run(name, s); err != nil {
+		return 0, err
+	}
+	return s.ExitCode(), nil
+}
+
+// ExitCode returns the exit code of the exit handlers once the service has
+// stopped.
+func (s *Service) ExitCode() int {
+	return s.code
+}
+
+// Execute reports that the service is running and handles the commands
+// sent by the Service Control Manager until the exit handlers complete.
+func (s *Service) Execute(
+	args []string,
+	r <-chan svc.ChangeRequest,
+	changes chan<- svc.Status) (bool, uint32) {
+
+	exited := make(chan int, 1)
+	s.m.SetExiter(func(code int) {
+		exited <- code
+		select {}
+	})
+
+	accepts := svc.AcceptStop | svc.AcceptShutdown
+	if s.pause {
+		accepts |= svc.AcceptPauseAndContinue
+	}
+	changes <- svc.Status{State: svc.StartPending}
+	changes <- svc.Status{State: svc.Running, Accepts: accepts}
+
+	var (
+		ticker     <-chan time.Time
+		checkpoint uint32
+		waitHint   = uint32(2 * s.checkpoint / time.Millisecond)
+	)
+	for {
+		select {
+		case c := <-r:
+			switch c.Cmd {
+			case svc.Interrogate:
+				changes <- c.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				if ticker != nil {
+					continue
+				}
+				checkpoint++
+				changes <- svc.Status{
+					State: svc.StopPending, CheckPoint: checkpoint, WaitHint: waitHint,
+				}
+				t := time.NewTicker(s.checkpoint)
+				defer t.Stop()
+				ticker = t.C
+				go s.stop()
+			case svc.Pause:
+				s.m.Trigger(PauseSignal)
+				changes <- svc.Status{State: svc.Paused, Accepts: accepts}
+			case svc.Continue:
+				s.m.Trigger(ContinueSignal)
+				changes <- svc.Status{State: svc.Running, Accepts: accepts}
+			}
+		case <-ticker:
+			checkpoint++
+			changes <- svc.Status{
+				State: svc.StopPending, CheckPoint: checkpoint, WaitHint: waitHint,
+			}
+		case code := <-exited:
+			s.code = code
+			changes <- svc.Status{State: svc.StopPending}
+			return code != 0, uint32(code)
+		}
+	}
+}
+
+// stop executes the exit handlers by triggering the Service's signal if it
+// is trapped or by invoking the Manager's Exit function.
+func (s *Service) stop() {
+	for _, spec := range s.m.State().Trapped {
+		if spec.Signal == s.sig {
+			s.m.Trigger(s.sig)
+			return
+		}
+	}
+	s.m.Exit(context.Background(), 0)
+}
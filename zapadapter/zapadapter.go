@@ -0,0 +1,42 @@
+/*
+Package zapadapter provides a goodbye.Logger that logs with zap.
+*/
+package zapadapter
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/thecodeteam/goodbye"
+)
+
+type logger struct {
+	l *zap.SugaredLogger
+}
+
+// New returns a goodbye.Logger that logs with the zap.Logger.
+func New(l *zap.Logger) goodbye.Logger {
+	return &logger{l: l.Sugar()}
+}
+
+// NewSugared returns a goodbye.Logger that logs with the
+// zap.SugaredLogger.
+func NewSugared(l *zap.SugaredLogger) goodbye.Logger {
+	return &logger{l: l}
+}
+
+// Log logs the message with the zap.SugaredLogger. The keyvals are
+// provided to the zap.SugaredLogger as loosely-typed key-value pairs.
+func (a *logger) Log(ctx context.Context, level goodbye.Level, msg string, keyvals ...interface{}) {
+	switch level {
+	case goodbye.LevelDebug:
+		a.l.Debugw(msg, keyvals...)
+	case goodbye.LevelInfo:
+		a.l.Infow(msg, keyvals...)
+	case goodbye.LevelWarn:
+		a.l.Warnw(msg, keyvals...)
+	default:
+		a.l.Errorw(msg, keyvals...)
+	}
+}